@@ -0,0 +1,121 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Contact struct {
+	// Unique ID for the contact.
+	ID int64 `json:"id"`
+
+	// An object containing the contact’s client id and name.
+	Customer *Customer `json:"client"`
+
+	// The title of the contact.
+	Title string `json:"title"`
+
+	// The first name of the contact.
+	FirstName string `json:"first_name"`
+
+	// The last name of the contact.
+	LastName string `json:"last_name"`
+
+	// The contact’s email address.
+	Email string `json:"email"`
+
+	// The contact’s office phone number.
+	PhoneOffice string `json:"phone_office"`
+
+	// The contact’s mobile phone number.
+	PhoneMobile string `json:"phone_mobile"`
+
+	// The contact’s fax number.
+	Fax string `json:"fax"`
+
+	// Date and time the contact was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Date and time the contact was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateContact describes a new contact, ClientID and FirstName are required.
+type CreateContact struct {
+	ClientID    int64  `json:"client_id"`
+	Title       string `json:"title,omitempty"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	PhoneOffice string `json:"phone_office,omitempty"`
+	PhoneMobile string `json:"phone_mobile,omitempty"`
+	Fax         string `json:"fax,omitempty"`
+}
+
+// UpdateContact holds the fields to change on a contact, nil and zero values
+// are left untouched.
+type UpdateContact struct {
+	ClientID    int64   `json:"client_id,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	FirstName   string  `json:"first_name,omitempty"`
+	LastName    *string `json:"last_name,omitempty"`
+	Email       *string `json:"email,omitempty"`
+	PhoneOffice *string `json:"phone_office,omitempty"`
+	PhoneMobile *string `json:"phone_mobile,omitempty"`
+	Fax         *string `json:"fax,omitempty"`
+}
+
+// Name returns the full name of the contact.
+func (c *Contact) Name() string {
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
+}
+
+// Recipient returns the contact as an invoice recipient.
+func (c *Contact) Recipient() *Recipient {
+	return &Recipient{
+		Name:  c.Name(),
+		Email: c.Email,
+	}
+}
+
+func (hv *Client) Contacts(ctx context.Context, opts ...ContactOption) iter.Seq2[*Contact, error] {
+	return fetchIter[Contact](ctx, hv, "contacts", "contacts", opts)
+}
+
+func (hv *Client) GetContact(ctx context.Context, id int64) (*Contact, error) {
+	url := fmt.Sprintf("%s/contacts/%d", hv.baseURL, id)
+	return doJSON[Contact](ctx, hv, "GET", url, nil, http.StatusOK, "load contact")
+}
+
+func (hv *Client) CreateContact(ctx context.Context, c *CreateContact) (*Contact, error) {
+	url := fmt.Sprintf("%s/contacts", hv.baseURL)
+	return doJSON[Contact](ctx, hv, "POST", url, c, http.StatusCreated, "create contact")
+}
+
+func (hv *Client) UpdateContact(ctx context.Context, id int64, c *UpdateContact) (*Contact, error) {
+	url := fmt.Sprintf("%s/contacts/%d", hv.baseURL, id)
+	return doJSON[Contact](ctx, hv, "PATCH", url, c, http.StatusOK, "update contact")
+}
+
+func (hv *Client) DeleteContact(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/contacts/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete contact")
+}
+
+// GetRecipients returns the contacts of a client as invoice recipients.
+func (hv *Client) GetRecipients(ctx context.Context, customer int64) ([]*Recipient, error) {
+	result := make([]*Recipient, 0)
+	for c, err := range hv.Contacts(ctx, WithClientID(customer)) {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, c.Recipient())
+	}
+	return result, nil
+}
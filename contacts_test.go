@@ -0,0 +1,67 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContacts(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+	srv.PerPage = 1
+
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	other := &harvest.Customer{Name: "Other"}
+	srv.AddCustomer(other)
+	srv.AddContact(&harvest.Contact{Customer: other, FirstName: "John"})
+
+	c, err := hv.CreateContact(ctx, &harvest.CreateContact{
+		ClientID:    acme.ID,
+		Title:       "CFO",
+		FirstName:   "Jane",
+		LastName:    "Doe",
+		Email:       "jane@example.com",
+		PhoneOffice: "+1 555 0100",
+	})
+	assert.NoError(err)
+	assert.Equal("ACME", c.Customer.Name)
+	assert.Equal("Jane Doe", c.Name())
+	assert.Equal("+1 555 0100", c.PhoneOffice)
+
+	_, err = hv.CreateContact(ctx, &harvest.CreateContact{ClientID: acme.ID, FirstName: "Joe", Email: "joe@example.com"})
+	assert.NoError(err)
+
+	mobile := "+1 555 0101"
+	c, err = hv.UpdateContact(ctx, c.ID, &harvest.UpdateContact{PhoneMobile: &mobile})
+	assert.NoError(err)
+	assert.Equal(mobile, c.PhoneMobile)
+	assert.Equal("CFO", c.Title)
+
+	c, err = hv.GetContact(ctx, c.ID)
+	assert.NoError(err)
+	assert.Equal(mobile, c.PhoneMobile)
+
+	// Follows pagination
+	r, err := hv.GetRecipients(ctx, acme.ID)
+	assert.NoError(err)
+	assert.Equal([]*harvest.Recipient{
+		{Name: "Joe", Email: "joe@example.com"},
+		{Name: "Jane Doe", Email: "jane@example.com"},
+	}, r)
+
+	assert.NoError(hv.DeleteContact(ctx, c.ID))
+	_, err = hv.GetContact(ctx, c.ID)
+	assert.True(harvest.IsNotFound(err))
+
+	count := 0
+	for _, err := range hv.Contacts(ctx) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(2, count)
+}
@@ -0,0 +1,91 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Customer is a Harvest client (renamed to avoid confusion with Client).
+type Customer struct {
+	// Unique ID for the client.
+	ID int64 `json:"id,omitempty"`
+
+	// A textual description of the client.
+	Name string `json:"name,omitempty"`
+
+	// Whether the client is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// The physical address for the client.
+	Address string `json:"address,omitempty"`
+
+	// Used to build a URL to your client’s invoice dashboard.
+	StatementKey string `json:"statement_key,omitempty"`
+
+	// The currency code associated with this client.
+	Currency string `json:"currency,omitempty"`
+
+	// Date and time the client was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the client was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateCustomer describes a new client, only Name is required.
+type CreateCustomer struct {
+	Name     string `json:"name"`
+	IsActive *bool  `json:"is_active,omitempty"`
+	Address  string `json:"address,omitempty"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// UpdateCustomer holds the fields to change on a client, nil and zero values
+// are left untouched.
+type UpdateCustomer struct {
+	Name     string  `json:"name,omitempty"`
+	IsActive *bool   `json:"is_active,omitempty"`
+	Address  *string `json:"address,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+func (hv *Client) Customers(ctx context.Context, opts ...ActiveOption) iter.Seq2[*Customer, error] {
+	return fetchIter[Customer](ctx, hv, "clients", "clients", opts)
+}
+
+func (hv *Client) FetchCustomers(ctx context.Context, opts ...ActiveOption) ([]*Customer, error) {
+	v := &url.Values{}
+	for _, o := range opts {
+		o.apply(v)
+	}
+	result, _, err := fetchAll[Customer](ctx, hv, fmt.Sprintf("%s/clients?%s", hv.baseURL, v.Encode()), "clients")
+	return result, err
+}
+
+func (hv *Client) GetCustomer(ctx context.Context, id int64) (*Customer, error) {
+	url := fmt.Sprintf("%s/clients/%d", hv.baseURL, id)
+	return doJSON[Customer](ctx, hv, "GET", url, nil, http.StatusOK, "load client")
+}
+
+func (hv *Client) CreateCustomer(ctx context.Context, c *CreateCustomer) (*Customer, error) {
+	url := fmt.Sprintf("%s/clients", hv.baseURL)
+	return doJSON[Customer](ctx, hv, "POST", url, c, http.StatusCreated, "create client")
+}
+
+func (hv *Client) UpdateCustomer(ctx context.Context, id int64, c *UpdateCustomer) (*Customer, error) {
+	url := fmt.Sprintf("%s/clients/%d", hv.baseURL, id)
+	return doJSON[Customer](ctx, hv, "PATCH", url, c, http.StatusOK, "update client")
+}
+
+// DeleteCustomer deletes a client. Harvest refuses this for clients that
+// still have projects, invoices or estimates, those can only be archived.
+func (hv *Client) DeleteCustomer(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/clients/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete client")
+}
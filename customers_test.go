@@ -0,0 +1,59 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomers(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddCustomer(&harvest.Customer{Name: "Existing", IsActive: true})
+
+	c, err := hv.CreateCustomer(ctx, &harvest.CreateCustomer{
+		Name:     "ACME",
+		Address:  "Main Street 1",
+		Currency: "USD",
+	})
+	assert.NoError(err)
+	assert.Equal("ACME", c.Name)
+	assert.Equal("USD", c.Currency)
+	assert.True(c.IsActive)
+	assert.False(c.CreatedAt.IsZero())
+	assert.NotNil(c.Hv)
+
+	_, err = hv.CreateCustomer(ctx, &harvest.CreateCustomer{Name: "ACME"})
+	assert.True(harvest.IsValidationError(err))
+
+	inactive := false
+	c, err = hv.UpdateCustomer(ctx, c.ID, &harvest.UpdateCustomer{IsActive: &inactive})
+	assert.NoError(err)
+	assert.False(c.IsActive)
+	assert.Equal("Main Street 1", c.Address)
+
+	c, err = hv.GetCustomer(ctx, c.ID)
+	assert.NoError(err)
+	assert.False(c.IsActive)
+
+	names := []string{}
+	for c, err := range hv.Customers(ctx) {
+		assert.NoError(err)
+		names = append(names, c.Name)
+	}
+	assert.Equal([]string{"ACME", "Existing"}, names)
+
+	// Can't delete clients with invoices
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: c.ID}})
+	assert.True(harvest.IsValidationError(hv.DeleteCustomer(ctx, c.ID)))
+
+	existing, err := hv.FetchCustomers(ctx)
+	assert.NoError(err)
+	assert.NoError(hv.DeleteCustomer(ctx, existing[1].ID))
+	_, err = hv.GetCustomer(ctx, existing[1].ID)
+	assert.True(harvest.IsNotFound(err))
+}
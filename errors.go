@@ -0,0 +1,119 @@
+package harvest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// APIError is returned when Harvest answers with an unexpected status code.
+// Use errors.As to inspect it, or one of the Is* helpers.
+type APIError struct {
+	// The HTTP status code of the response.
+	StatusCode int `json:"-"`
+
+	// Method and URL of the failed request.
+	Method string `json:"-"`
+	URL    string `json:"-"`
+
+	// The error code and description sent by Harvest (for instance
+	// "invalid_token"), if any.
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+
+	// Validation message, sent by Harvest on 422 Unprocessable Entity.
+	Message string `json:"message"`
+
+	// How long to wait before retrying, as indicated by the Retry-After
+	// header. Zero if not set.
+	RetryAfter time.Duration `json:"-"`
+
+	// The raw response body.
+	Body []byte `json:"-"`
+
+	action string
+}
+
+// Limit how much of an error response we keep around.
+const maxErrorBody = 64 * 1024
+
+func newAPIError(resp *http.Response, action string) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		action:     action,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e.Body = body
+	_ = json.Unmarshal(body, e)
+	return e
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("Failed to %s: %d", e.action, e.StatusCode)
+	switch {
+	case e.Description != "":
+		msg += ": " + e.Description
+	case e.Message != "":
+		msg += ": " + e.Message
+	case e.Code != "":
+		msg += ": " + e.Code
+	}
+	return msg
+}
+
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+func hasStatus(err error, code int) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.StatusCode == code
+}
+
+// IsNotFound reports whether err is an API error for a missing resource.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an API error caused by exceeding the
+// Harvest rate limit.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsUnauthorized reports whether err is an API error caused by invalid
+// credentials.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an API error caused by insufficient
+// permissions.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsValidationError reports whether err is an API error caused by invalid
+// input (422 Unprocessable Entity).
+func IsValidationError(err error) bool {
+	return hasStatus(err, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,213 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type Estimate struct {
+	ID             int64     `json:"id,omitempty"`
+	ClientID       int64     `json:"client_id,omitempty"`
+	ClientKey      string    `json:"client_key,omitempty"`
+	Number         string    `json:"number,omitempty"`
+	PurchaseOrder  string    `json:"purchase_order,omitempty"`
+	State          string    `json:"state,omitempty"`
+	SentAt         time.Time `json:"sent_at,omitempty"`
+	AcceptedAt     time.Time `json:"accepted_at,omitempty"`
+	DeclinedAt     time.Time `json:"declined_at,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+	Customer       *Customer `json:"client,omitempty"`
+	Creator        *UserRef  `json:"creator,omitempty"`
+	Amount         float64   `json:"amount,omitempty"`
+	Tax            float64   `json:"tax,omitempty"`
+	TaxAmount      float64   `json:"tax_amount,omitempty"`
+	Tax2           float64   `json:"tax2,omitempty"`
+	Tax2Amount     float64   `json:"tax2_amount,omitempty"`
+	Discount       float64   `json:"discount,omitempty"`
+	DiscountAmount float64   `json:"discount_amount,omitempty"`
+	Subject        string    `json:"subject,omitempty"`
+	Notes          string    `json:"notes,omitempty"`
+	Currency       string    `json:"currency,omitempty"`
+	IssueDate      string    `json:"issue_date,omitempty"`
+
+	LineItems []*LineItem `json:"line_items,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// UpdateEstimate holds the fields to change on an estimate, nil and zero
+// values are left untouched.
+//
+// Line items with an ID are updated (or removed when Destroy is set), those
+// without are added.
+type UpdateEstimate struct {
+	ClientID      int64       `json:"client_id,omitempty"`
+	Number        string      `json:"number,omitempty"`
+	PurchaseOrder *string     `json:"purchase_order,omitempty"`
+	Tax           *float64    `json:"tax,omitempty"`
+	Tax2          *float64    `json:"tax2,omitempty"`
+	Discount      *float64    `json:"discount,omitempty"`
+	Subject       *string     `json:"subject,omitempty"`
+	Notes         *string     `json:"notes,omitempty"`
+	Currency      string      `json:"currency,omitempty"`
+	IssueDate     string      `json:"issue_date,omitempty"`
+	LineItems     []*LineItem `json:"line_items,omitempty"`
+}
+
+type EstimateMessage struct {
+	// Unique ID for the message.
+	ID int64 `json:"id"`
+
+	// Name of the user that created the message.
+	SentBy string `json:"sent_by"`
+
+	// Email of the user that created the message.
+	SentByEmail string `json:"sent_by_email"`
+
+	// Name of the user that the message was sent from.
+	SentFrom string `json:"sent_from"`
+
+	// Email of the user that the message was sent from.
+	SentFromEmail string `json:"sent_from_email"`
+
+	// The recipients of the message.
+	Recipients []*Recipient `json:"recipients"`
+
+	// The message subject.
+	Subject string `json:"subject"`
+
+	// The message body.
+	Body string `json:"body"`
+
+	// Whether to email a copy of the message to the current user.
+	SendMeACopy bool `json:"send_me_a_copy"`
+
+	// The type of estimate event that occurred with the message: send,
+	// accept, decline, re-open, view or invoice. Empty for sent messages.
+	EventType string `json:"event_type"`
+
+	// Date and time the message was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Date and time the message was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateEstimateMessage is an email to send an estimate to its client.
+type CreateEstimateMessage struct {
+	Recipients  []*Recipient `json:"recipients"`
+	Subject     string       `json:"subject,omitempty"`
+	Body        string       `json:"body,omitempty"`
+	SendMeACopy bool         `json:"send_me_a_copy,omitempty"`
+}
+
+func (hv *Client) Estimates(ctx context.Context, opts ...EstimateOption) iter.Seq2[*Estimate, error] {
+	return fetchIter[Estimate](ctx, hv, "estimates", "estimates", opts)
+}
+
+func (hv *Client) GetEstimate(ctx context.Context, id int64) (*Estimate, error) {
+	url := fmt.Sprintf("%s/estimates/%d", hv.baseURL, id)
+	return doJSON[Estimate](ctx, hv, "GET", url, nil, http.StatusOK, "load estimate")
+}
+
+// CreateEstimate creates a new estimate, ClientID is required.
+func (hv *Client) CreateEstimate(ctx context.Context, estimate *Estimate) (*Estimate, error) {
+	url := fmt.Sprintf("%s/estimates", hv.baseURL)
+	return doJSON[Estimate](ctx, hv, "POST", url, estimate, http.StatusCreated, "create estimate")
+}
+
+// Update changes the estimate and updates e with the result.
+func (e *Estimate) Update(ctx context.Context, u *UpdateEstimate) error {
+	url := fmt.Sprintf("%s/estimates/%d", e.Hv.baseURL, e.ID)
+	r, err := doJSON[Estimate](ctx, e.Hv, "PATCH", url, u, http.StatusOK, "update estimate")
+	if err != nil {
+		return err
+	}
+
+	*e = *r
+	return nil
+}
+
+func (e *Estimate) Delete(ctx context.Context) error {
+	url := fmt.Sprintf("%s/estimates/%d", e.Hv.baseURL, e.ID)
+	return e.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete estimate")
+}
+
+// Send emails the estimate to its client.
+func (e *Estimate) Send(ctx context.Context, m *CreateEstimateMessage) (*EstimateMessage, error) {
+	url := fmt.Sprintf("%s/estimates/%d/messages", e.Hv.baseURL, e.ID)
+	return doJSON[EstimateMessage](ctx, e.Hv, "POST", url, m, http.StatusCreated, "send estimate")
+}
+
+// MarkSent marks a draft estimate as sent, without emailing it.
+func (e *Estimate) MarkSent(ctx context.Context) error {
+	return e.event(ctx, "send", "mark estimate as sent")
+}
+
+// Accept marks an open estimate as accepted.
+func (e *Estimate) Accept(ctx context.Context) error {
+	return e.event(ctx, "accept", "accept estimate")
+}
+
+// Decline marks an open estimate as declined.
+func (e *Estimate) Decline(ctx context.Context) error {
+	return e.event(ctx, "decline", "decline estimate")
+}
+
+// Reopen re-opens an accepted or declined estimate.
+func (e *Estimate) Reopen(ctx context.Context) error {
+	return e.event(ctx, "re-open", "re-open estimate")
+}
+
+func (e *Estimate) event(ctx context.Context, event, action string) error {
+	url := fmt.Sprintf("%s/estimates/%d/messages", e.Hv.baseURL, e.ID)
+	return e.Hv.call(ctx, "POST", url, eventRequest{
+		EventType: event,
+	}, http.StatusCreated, action)
+}
+
+// Messages lists the messages and events of the estimate.
+func (e *Estimate) Messages(ctx context.Context) iter.Seq2[*EstimateMessage, error] {
+	return fetchIter[EstimateMessage, PageOption](ctx, e.Hv, "estimate_messages", fmt.Sprintf("estimates/%d/messages", e.ID), nil)
+}
+
+func (e *Estimate) DeleteMessage(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/estimates/%d/messages/%d", e.Hv.baseURL, e.ID, id)
+	return e.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete estimate message")
+}
+
+// NewInvoice returns a new invoice based on the estimate, to be passed to
+// CreateInvoice.
+func (e *Estimate) NewInvoice() *Invoice {
+	clientID := e.ClientID
+	if e.Customer != nil {
+		clientID = e.Customer.ID
+	}
+
+	items := make([]*LineItem, 0, len(e.LineItems))
+	for _, li := range e.LineItems {
+		item := *li
+		item.ID = 0
+		item.Amount = 0
+		items = append(items, &item)
+	}
+
+	return &Invoice{
+		ClientID:      clientID,
+		EstimateID:    e.ID,
+		PurchaseOrder: e.PurchaseOrder,
+		Tax:           e.Tax,
+		Tax2:          e.Tax2,
+		Discount:      e.Discount,
+		Subject:       e.Subject,
+		Notes:         e.Notes,
+		Currency:      e.Currency,
+		LineItems:     items,
+	}
+}
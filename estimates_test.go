@@ -0,0 +1,88 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimates(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	srv.AddEstimate(&harvest.Estimate{Customer: acme, Subject: "Old"})
+
+	e, err := hv.CreateEstimate(ctx, &harvest.Estimate{
+		ClientID: acme.ID,
+		Subject:  "Website",
+		LineItems: []*harvest.LineItem{
+			{Kind: "Service", Description: "Design", Quantity: 10, UnitPrice: 100},
+			{Kind: "Service", Description: "Build", Quantity: 20, UnitPrice: 100},
+		},
+	})
+	assert.NoError(err)
+	assert.Equal("draft", e.State)
+	assert.Equal(3000.0, e.Amount)
+	assert.Equal("ACME", e.Customer.Name)
+	assert.Len(e.LineItems, 2)
+
+	notes := "Valid for 30 days"
+	err = e.Update(ctx, &harvest.UpdateEstimate{
+		Notes: &notes,
+		LineItems: []*harvest.LineItem{
+			{ID: e.LineItems[1].ID, Quantity: 30},
+			{Kind: "Service", Description: "Hosting", Quantity: 1, UnitPrice: 500},
+		},
+	})
+	assert.NoError(err)
+	assert.Equal(notes, e.Notes)
+	assert.Len(e.LineItems, 3)
+	assert.Equal(4500.0, e.Amount)
+
+	m, err := e.Send(ctx, &harvest.CreateEstimateMessage{
+		Recipients: []*harvest.Recipient{{Name: "Jane Doe", Email: "jane@example.com"}},
+		Subject:    "Our estimate",
+	})
+	assert.NoError(err)
+	assert.Equal("Our estimate", m.Subject)
+	assert.Equal("sent", srv.Estimate(e.ID).State)
+
+	assert.NoError(e.Decline(ctx))
+	assert.Equal("declined", srv.Estimate(e.ID).State)
+	assert.True(harvest.IsValidationError(e.Accept(ctx)))
+	assert.NoError(e.Reopen(ctx))
+	assert.NoError(e.Accept(ctx))
+
+	e, err = hv.GetEstimate(ctx, e.ID)
+	assert.NoError(err)
+	assert.Equal("accepted", e.State)
+	assert.False(e.AcceptedAt.IsZero())
+
+	events := []string{}
+	for m, err := range e.Messages(ctx) {
+		assert.NoError(err)
+		events = append(events, m.EventType)
+	}
+	assert.Equal([]string{"accept", "re-open", "decline", ""}, events)
+
+	inv, err := hv.CreateInvoice(ctx, e.NewInvoice())
+	assert.NoError(err)
+	assert.Equal(4500.0, inv.Amount)
+	assert.Equal("Website", inv.Subject)
+
+	count := 0
+	for _, err := range hv.Estimates(ctx, harvest.WithClientID(acme.ID)) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(2, count)
+
+	assert.NoError(e.Delete(ctx))
+	_, err = hv.GetEstimate(ctx, e.ID)
+	assert.True(harvest.IsNotFound(err))
+}
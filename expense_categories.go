@@ -0,0 +1,94 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ExpenseCategory struct {
+	// Unique ID for the expense category.
+	ID int64 `json:"id"`
+
+	// The name of the expense category.
+	Name string `json:"name"`
+
+	// The unit name of the expense category.
+	UnitName string `json:"unit_name,omitempty"`
+
+	// The unit price of the expense category.
+	UnitPrice float64 `json:"unit_price,omitempty"`
+
+	// Whether the expense category is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Date and time the expense category was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the expense category was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateExpenseCategory describes a new expense category, only Name is
+// required. Set UnitName and UnitPrice for categories that are charged per
+// unit (e.g. mileage).
+type CreateExpenseCategory struct {
+	Name      string  `json:"name"`
+	UnitName  string  `json:"unit_name,omitempty"`
+	UnitPrice float64 `json:"unit_price,omitempty"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+}
+
+// UpdateExpenseCategory holds the fields to change on an expense category,
+// nil and zero values are left untouched.
+type UpdateExpenseCategory struct {
+	Name      string   `json:"name,omitempty"`
+	UnitName  *string  `json:"unit_name,omitempty"`
+	UnitPrice *float64 `json:"unit_price,omitempty"`
+	IsActive  *bool    `json:"is_active,omitempty"`
+}
+
+func (hv *Client) ExpenseCategories(ctx context.Context, opts ...ActiveOption) iter.Seq2[*ExpenseCategory, error] {
+	return fetchIter[ExpenseCategory](ctx, hv, "expense_categories", "expense_categories", opts)
+}
+
+func (hv *Client) GetExpenseCategory(ctx context.Context, id int64) (*ExpenseCategory, error) {
+	url := fmt.Sprintf("%s/expense_categories/%d", hv.baseURL, id)
+	return doJSON[ExpenseCategory](ctx, hv, "GET", url, nil, http.StatusOK, "load expense category")
+}
+
+// FindExpenseCategory looks up an expense category by name, ignoring case.
+// Returns nil if there is no such category.
+func (hv *Client) FindExpenseCategory(ctx context.Context, name string) (*ExpenseCategory, error) {
+	for c, err := range hv.ExpenseCategories(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (hv *Client) CreateExpenseCategory(ctx context.Context, c *CreateExpenseCategory) (*ExpenseCategory, error) {
+	url := fmt.Sprintf("%s/expense_categories", hv.baseURL)
+	return doJSON[ExpenseCategory](ctx, hv, "POST", url, c, http.StatusCreated, "create expense category")
+}
+
+func (hv *Client) UpdateExpenseCategory(ctx context.Context, id int64, c *UpdateExpenseCategory) (*ExpenseCategory, error) {
+	url := fmt.Sprintf("%s/expense_categories/%d", hv.baseURL, id)
+	return doJSON[ExpenseCategory](ctx, hv, "PATCH", url, c, http.StatusOK, "update expense category")
+}
+
+// DeleteExpenseCategory deletes an expense category. Categories used by any
+// expense fail with a validation error.
+func (hv *Client) DeleteExpenseCategory(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/expense_categories/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete expense category")
+}
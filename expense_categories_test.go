@@ -0,0 +1,81 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpenseCategories(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddExpenseCategory(&harvest.ExpenseCategory{Name: "Meals"})
+
+	c, err := hv.CreateExpenseCategory(ctx, &harvest.CreateExpenseCategory{
+		Name:      "Mileage",
+		UnitName:  "mile",
+		UnitPrice: 0.5,
+	})
+	assert.NoError(err)
+	assert.Equal("Mileage", c.Name)
+	assert.Equal("mile", c.UnitName)
+	assert.Equal(0.5, c.UnitPrice)
+	assert.True(c.IsActive)
+	assert.NotNil(c.Hv)
+
+	_, err = hv.CreateExpenseCategory(ctx, &harvest.CreateExpenseCategory{Name: "Mileage"})
+	assert.True(harvest.IsValidationError(err))
+
+	found, err := hv.FindExpenseCategory(ctx, "mileage")
+	assert.NoError(err)
+	if assert.NotNil(found) {
+		assert.Equal(c.ID, found.ID)
+	}
+	found, err = hv.FindExpenseCategory(ctx, "Lodging")
+	assert.NoError(err)
+	assert.Nil(found)
+
+	// Unit based categories compute the total cost
+	e, err := hv.CreateExpense(ctx, &harvest.CreateExpense{
+		ProjectID:         1,
+		ExpenseCategoryID: c.ID,
+		SpentDate:         "2024-01-02",
+		Units:             100,
+	})
+	assert.NoError(err)
+	assert.Equal(50.0, e.TotalCost)
+	assert.Equal("Mileage", e.ExpenseCategory.Name)
+
+	price := 0.6
+	inactive := false
+	c, err = hv.UpdateExpenseCategory(ctx, c.ID, &harvest.UpdateExpenseCategory{UnitPrice: &price, IsActive: &inactive})
+	assert.NoError(err)
+	assert.Equal(0.6, c.UnitPrice)
+	assert.False(c.IsActive)
+	assert.Equal("mile", c.UnitName)
+
+	c, err = hv.GetExpenseCategory(ctx, c.ID)
+	assert.NoError(err)
+	assert.False(c.IsActive)
+
+	names := []string{}
+	for c, err := range hv.ExpenseCategories(ctx) {
+		assert.NoError(err)
+		names = append(names, c.Name)
+	}
+	assert.Equal([]string{"Mileage", "Meals"}, names)
+
+	// Can't delete categories that are in use
+	assert.True(harvest.IsValidationError(hv.DeleteExpenseCategory(ctx, c.ID)))
+	assert.NotNil(srv.ExpenseCategory(c.ID))
+
+	meals, err := hv.FindExpenseCategory(ctx, "Meals")
+	assert.NoError(err)
+	assert.NoError(hv.DeleteExpenseCategory(ctx, meals.ID))
+	_, err = hv.GetExpenseCategory(ctx, meals.ID)
+	assert.True(harvest.IsNotFound(err))
+}
@@ -0,0 +1,346 @@
+package harvest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type Expense struct {
+	// Unique ID for the expense.
+	ID int64 `json:"id"`
+
+	// An object containing the expense’s client id, name, and currency.
+	Customer *Customer `json:"client,omitempty"`
+
+	// An object containing the associated project’s id, name, and code.
+	Project *Project `json:"project"`
+
+	// An object containing the associated expense category’s id, name,
+	// unit_price, and unit_name.
+	ExpenseCategory *ExpenseCategory `json:"expense_category,omitempty"`
+
+	// An object containing the id and name of the user that recorded the
+	// expense.
+	User *UserRef `json:"user,omitempty"`
+
+	// An object containing the expense’s receipt URL and file name.
+	Receipt *Receipt `json:"receipt,omitempty"`
+
+	// Once the expense has been invoiced, this field will include the
+	// associated invoice’s id and number.
+	Invoice *InvoiceRef `json:"invoice,omitempty"`
+
+	// Textual notes used to describe the expense.
+	Notes string `json:"notes"`
+
+	// The quantity of units to use in calculating the total cost of the
+	// expense.
+	Units float64 `json:"units,omitempty"`
+
+	// The total amount of the expense.
+	TotalCost float64 `json:"total_cost"`
+
+	// Whether the expense is billable or not.
+	Billable bool `json:"billable,omitempty"`
+
+	// Whether the expense has been approved or closed for some other reason.
+	IsClosed bool `json:"is_closed,omitempty"`
+
+	// Whether the expense has been been invoiced, approved, or the project or
+	// person related to the expense is archived.
+	IsLocked bool `json:"is_locked,omitempty"`
+
+	// An explanation of why the expense has been locked.
+	LockedReason string `json:"locked_reason,omitempty"`
+
+	// Whether or not the expense has been marked as invoiced.
+	IsBilled bool `json:"is_billed,omitempty"`
+
+	// The approval status of the expense: unsubmitted, submitted or
+	// approved.
+	ApprovalStatus string `json:"approval_status,omitempty"`
+
+	// Date the expense occurred.
+	SpentDate string `json:"spent_date"`
+
+	// Date and time the expense was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the expense was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+type Receipt struct {
+	URL         string `json:"url"`
+	FileName    string `json:"file_name"`
+	FileSize    int64  `json:"file_size"`
+	ContentType string `json:"content_type"`
+}
+
+func (hv *Client) Expenses(ctx context.Context, opts ...ExpenseOption) iter.Seq2[*Expense, error] {
+	return fetchIter[Expense](ctx, hv, "expenses", "expenses", opts)
+}
+
+func (hv *Client) FetchExpenses(ctx context.Context, opts ...ExpenseOption) ([]*Expense, error) {
+	v := &url.Values{}
+	for _, o := range opts {
+		o.apply(v)
+	}
+	result, _, err := fetchAll[Expense](ctx, hv, fmt.Sprintf("%s/expenses?%s", hv.baseURL, v.Encode()), "expenses")
+	return result, err
+}
+
+func (hv *Client) GetExpense(ctx context.Context, id int64) (*Expense, error) {
+	url := fmt.Sprintf("%s/expenses/%d", hv.baseURL, id)
+	return doJSON[Expense](ctx, hv, "GET", url, nil, http.StatusOK, "load expense")
+}
+
+// CreateExpense describes a new expense. ProjectID, ExpenseCategoryID and
+// SpentDate are required. A receipt can be uploaded by setting File.
+type CreateExpense struct {
+	ProjectID         int64   `json:"project_id"`
+	ExpenseCategoryID int64   `json:"expense_category_id"`
+	SpentDate         string  `json:"spent_date"`
+	UserID            int64   `json:"user_id,omitempty"`
+	Units             float64 `json:"units,omitempty"`
+	TotalCost         float64 `json:"total_cost,omitempty"`
+	Notes             string  `json:"notes,omitempty"`
+	Billable          *bool   `json:"billable,omitempty"`
+
+	Filename    string    `json:"-"`
+	ContentType string    `json:"-"`
+	File        io.Reader `json:"-"`
+}
+
+func (hv *Client) CreateExpense(ctx context.Context, e *CreateExpense) (*Expense, error) {
+	url := fmt.Sprintf("%s/expenses", hv.baseURL)
+	if e.File == nil {
+		return doJSON[Expense](ctx, hv, "POST", url, e, http.StatusCreated, "create expense")
+	}
+	return doMultipart[Expense](ctx, hv, "POST", url, e, &upload{
+		Field:       "receipt",
+		Filename:    e.Filename,
+		ContentType: e.ContentType,
+		File:        e.File,
+	}, http.StatusCreated, "create expense")
+}
+
+// UpdateExpense holds the fields to change on an expense, nil and zero values
+// are left untouched.
+//
+// Setting File replaces the receipt, DeleteReceipt removes it.
+type UpdateExpense struct {
+	ProjectID         int64    `json:"project_id,omitempty"`
+	ExpenseCategoryID int64    `json:"expense_category_id,omitempty"`
+	SpentDate         string   `json:"spent_date,omitempty"`
+	Units             *float64 `json:"units,omitempty"`
+	TotalCost         *float64 `json:"total_cost,omitempty"`
+	Notes             *string  `json:"notes,omitempty"`
+	Billable          *bool    `json:"billable,omitempty"`
+	DeleteReceipt     bool     `json:"delete_receipt,omitempty"`
+
+	Filename    string    `json:"-"`
+	ContentType string    `json:"-"`
+	File        io.Reader `json:"-"`
+}
+
+func (e *Expense) Update(ctx context.Context, u *UpdateExpense) error {
+	url := fmt.Sprintf("%s/expenses/%d", e.Hv.baseURL, e.ID)
+
+	var r *Expense
+	var err error
+	if u.File == nil {
+		r, err = doJSON[Expense](ctx, e.Hv, "PATCH", url, u, http.StatusOK, "update expense")
+	} else {
+		r, err = doMultipart[Expense](ctx, e.Hv, "PATCH", url, u, &upload{
+			Field:       "receipt",
+			Filename:    u.Filename,
+			ContentType: u.ContentType,
+			File:        u.File,
+		}, http.StatusOK, "update expense")
+	}
+	if err != nil {
+		return err
+	}
+	*e = *r
+	return nil
+}
+
+// Delete deletes the expense. Expenses that are locked (e.g. because they
+// have been invoiced or approved) can't be deleted.
+func (e *Expense) Delete(ctx context.Context) error {
+	url := fmt.Sprintf("%s/expenses/%d", e.Hv.baseURL, e.ID)
+	return e.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete expense")
+}
+
+// DownloadReceipt returns the receipt attached to the expense, the caller
+// should close it when done.
+//
+// Receipts may be stored outside of Harvest, the credentials are only sent
+// along when the receipt is on the API or company host.
+func (e *Expense) DownloadReceipt(ctx context.Context) (io.ReadCloser, error) {
+	if e.Receipt == nil || e.Receipt.URL == "" {
+		return nil, fmt.Errorf("Expense %d has no receipt", e.ID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", e.Receipt.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := e.Hv.isHarvestURL(ctx, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		e.Hv.authorize(req)
+	}
+
+	resp, err := e.Hv.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp, "download receipt")
+	}
+	return resp.Body, nil
+}
+
+// upload is a file sent along in a multipart request.
+type upload struct {
+	Field       string
+	Filename    string
+	ContentType string
+	File        io.Reader
+}
+
+// doMultipart is doJSON for requests that upload a file. The fields of body
+// are sent as form values, using their JSON names.
+func doMultipart[T any](ctx context.Context, hv *Client, method, url string, body any, file *upload, expect int, action string) (*T, error) {
+	fields, err := formValues(body)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	mp := multipart.NewWriter(pw)
+
+	var g errgroup.Group
+	g.Go(func() (err error) {
+		// Make sure a failed write aborts the upload instead of sending a
+		// truncated body.
+		defer func() {
+			pw.CloseWithError(err)
+		}()
+
+		for _, f := range fields {
+			err := mp.WriteField(f[0], f[1])
+			if err != nil {
+				return err
+			}
+		}
+
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, file.Field, file.Filename))
+		h.Set("Content-Type", file.ContentType)
+
+		fw, err := mp.CreatePart(h)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(fw, file.File)
+		if err != nil {
+			return err
+		}
+
+		return mp.Close()
+	})
+
+	result := new(T)
+	g.Go(func() (err error) {
+		// Unblock the writer when the request fails or gets cancelled.
+		defer func() {
+			if err != nil {
+				pr.CloseWithError(err)
+			}
+		}()
+
+		req, err := http.NewRequestWithContext(ctx, method, url, pr)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", mp.FormDataContentType())
+		hv.authorize(req)
+
+		resp, err := hv.do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != expect {
+			return newAPIError(resp, action)
+		}
+
+		return json.NewDecoder(resp.Body).Decode(result)
+	})
+
+	err = g.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	setClient(hv, result)
+	return result, nil
+}
+
+// formValues flattens the JSON representation of v into form fields, sorted
+// by name.
+func formValues(v any) ([][2]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]any)
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][2]string, 0, len(m))
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case string:
+			result = append(result, [2]string{k, v})
+		case float64:
+			result = append(result, [2]string{k, strconv.FormatFloat(v, 'f', -1, 64)})
+		case bool:
+			result = append(result, [2]string{k, strconv.FormatBool(v)})
+		default:
+			return nil, fmt.Errorf("Unsupported form value for %s: %T", k, v)
+		}
+	}
+	slices.SortFunc(result, func(a, b [2]string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package harvest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateExpense(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	billable := false
+	e, err := hv.CreateExpense(ctx, &harvest.CreateExpense{
+		ProjectID:         1,
+		ExpenseCategoryID: 2,
+		SpentDate:         "2024-01-02",
+		TotalCost:         12.5,
+		Billable:          &billable,
+	})
+	assert.NoError(err)
+	assert.Equal(12.5, e.TotalCost)
+	assert.False(e.Billable)
+	assert.Equal("unsubmitted", e.ApprovalStatus)
+	assert.Nil(e.Receipt)
+
+	_, err = e.DownloadReceipt(ctx)
+	assert.Error(err)
+
+	// Plain update
+	notes := "Dinner"
+	cost := 20.0
+	err = e.Update(ctx, &harvest.UpdateExpense{Notes: &notes, TotalCost: &cost})
+	assert.NoError(err)
+	assert.Equal("Dinner", e.Notes)
+	assert.Equal(20.0, e.TotalCost)
+	assert.Equal("2024-01-02", e.SpentDate)
+
+	// Add a receipt, the other fields go along in the form
+	cost = 25
+	billable = true
+	err = e.Update(ctx, &harvest.UpdateExpense{
+		TotalCost:   &cost,
+		Billable:    &billable,
+		Filename:    "receipt.png",
+		ContentType: "image/png",
+		File:        strings.NewReader("PNG receipt"),
+	})
+	assert.NoError(err)
+	assert.Equal(25.0, e.TotalCost)
+	assert.True(e.Billable)
+	assert.Equal("Dinner", e.Notes)
+	if assert.NotNil(e.Receipt) {
+		assert.Equal("receipt.png", e.Receipt.FileName)
+		assert.Equal("image/png", e.Receipt.ContentType)
+		assert.Equal(int64(11), e.Receipt.FileSize)
+	}
+
+	r, err := e.DownloadReceipt(ctx)
+	assert.NoError(err)
+	data, err := io.ReadAll(r)
+	assert.NoError(err)
+	assert.NoError(r.Close())
+	assert.Equal("PNG receipt", string(data))
+
+	// Replace it
+	err = e.Update(ctx, &harvest.UpdateExpense{
+		Filename:    "receipt.pdf",
+		ContentType: "application/pdf",
+		File:        strings.NewReader("%PDF-1.4"),
+	})
+	assert.NoError(err)
+	assert.Equal("receipt.pdf", e.Receipt.FileName)
+	r, err = e.DownloadReceipt(ctx)
+	assert.NoError(err)
+	data, _ = io.ReadAll(r)
+	r.Close()
+	assert.Equal("%PDF-1.4", string(data))
+
+	// And remove it
+	err = e.Update(ctx, &harvest.UpdateExpense{DeleteReceipt: true})
+	assert.NoError(err)
+	assert.Nil(e.Receipt)
+	assert.Nil(srv.Expense(e.ID).Receipt)
+}
+
+func TestDeleteExpense(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	e := &harvest.Expense{Project: &harvest.Project{ID: 1}, TotalCost: 10}
+	srv.AddExpense(e)
+	locked := &harvest.Expense{Project: &harvest.Project{ID: 1}, TotalCost: 10, IsLocked: true}
+	srv.AddExpense(locked)
+
+	e, err := hv.GetExpense(ctx, e.ID)
+	assert.NoError(err)
+	assert.NoError(e.Delete(ctx))
+	assert.Nil(srv.Expense(e.ID))
+
+	_, err = hv.GetExpense(ctx, e.ID)
+	assert.True(harvest.IsNotFound(err))
+
+	locked, err = hv.GetExpense(ctx, locked.ID)
+	assert.NoError(err)
+	err = locked.Delete(ctx)
+	assert.True(harvest.IsValidationError(err))
+	assert.NotNil(srv.Expense(locked.ID))
+}
+
+func TestDownloadForeignReceipt(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	var header http.Header
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		_, _ = io.WriteString(w, "Stored receipt")
+	}))
+	defer storage.Close()
+
+	exp := &harvest.Expense{Project: &harvest.Project{ID: 1}, TotalCost: 10}
+	srv.AddExpense(exp)
+	e, err := hv.GetExpense(ctx, exp.ID)
+	assert.NoError(err)
+
+	// Receipts stored elsewhere don't get the credentials
+	e.Receipt = &harvest.Receipt{URL: storage.URL + "/receipt.png", FileName: "receipt.png"}
+	r, err := e.DownloadReceipt(ctx)
+	assert.NoError(err)
+	data, err := io.ReadAll(r)
+	assert.NoError(err)
+	assert.NoError(r.Close())
+	assert.Equal("Stored receipt", string(data))
+	assert.Empty(header.Get("Authorization"))
+	assert.Empty(header.Get("Harvest-Account-ID"))
+
+	// Those on the API host do
+	e.Hv, err = harvest.New(1, "token", harvest.WithBaseURL(storage.URL+"/v2"))
+	assert.NoError(err)
+	r, err = e.DownloadReceipt(ctx)
+	assert.NoError(err)
+	assert.NoError(r.Close())
+	assert.Equal("Bearer token", header.Get("Authorization"))
+}
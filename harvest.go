@@ -2,33 +2,49 @@ package harvest
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"iter"
-	"mime/multipart"
 	"net/http"
 	"net/http/cookiejar"
-	"net/textproto"
 	"net/url"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/juju/ratelimit"
-	"golang.org/x/sync/errgroup"
 )
 
-const serverUrl = "https://api.harvestapp.com/v2"
+const (
+	defaultBaseURL   = "https://api.harvestapp.com/v2"
+	defaultUserAgent = "harvest-go (https://github.com/rubenv/harvest)"
+)
 
 type Client struct {
 	accountID int64
 	token     string
+
+	// Cached company info, see GetCompanyInfo.
+	companyMu sync.Mutex
 	company   *Company
 
-	client *http.Client
-	bucket *ratelimit.Bucket
+	baseURL   string
+	userAgent string
+	client    *http.Client
+	bucket    *ratelimit.Bucket
+	retry     RetryPolicy
+
+	// Number of pages listings fetch ahead, see WithPrefetch.
+	prefetch int
+
+	// Cached invoice item category names, see WithKindValidation.
+	validateKinds bool
+	kindsMu       sync.Mutex
+	kinds         map[string]bool
 }
 
 type Company struct {
@@ -53,6 +69,7 @@ type Company struct {
 type Invoice struct {
 	ID             int64     `json:"id,omitempty"`
 	ClientID       int64     `json:"client_id,omitempty"`
+	EstimateID     int64     `json:"estimate_id,omitempty"`
 	ClientKey      string    `json:"client_key,omitempty"`
 	Number         string    `json:"number,omitempty"`
 	PurchaseOrder  string    `json:"purchase_order,omitempty"`
@@ -85,18 +102,8 @@ type Invoice struct {
 
 	LineItems []*LineItem `json:"line_items,omitempty"`
 
-	Hv *Client `json:"-"`
-}
-
-type Expense struct {
-	ID int64 `json:"id"`
-
-	// An object containing the associated project’s id, name, and code.
-	Project *Project `json:"project"`
-
-	SpentDate string  `json:"spent_date"`
-	Notes     string  `json:"notes"`
-	TotalCost float64 `json:"total_cost"`
+	// Only used when creating an invoice, see CreateInvoice.
+	LineItemsImport *LineItemsImport `json:"line_items_import,omitempty"`
 
 	Hv *Client `json:"-"`
 }
@@ -135,19 +142,9 @@ type LineItem struct {
 
 	// Whether the invoice’s tax2 percentage applies to this line item.
 	Taxed2 bool `json:"taxed_2,omitempty"`
-}
-
-type Project struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
-	Code string `json:"code"`
-}
-
-type Customer struct {
-	ID   int64  `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
 
-	Hv *Client `json:"-"`
+	// Set to remove the line item when updating an invoice or estimate.
+	Destroy bool `json:"_destroy,omitempty"`
 }
 
 type Recipient struct {
@@ -155,97 +152,49 @@ type Recipient struct {
 	Email string `json:"email"`
 }
 
-type Payment struct {
-	// Unique ID for the payment.
-	ID int64 `json:"id"`
-
-	// The amount of the payment.
-	Amount float64 `json:"amount"`
-
-	// Date and time the payment was made.
-	PaidAt time.Time `json:"paid_at"`
-
-	// Date the payment was made.
-	PaidDate string `json:"paid_date"`
-
-	// The name of the person who recorded the payment.
-	RecordedBy string `json:"recorded_by"`
-
-	// The email of the person who recorded the payment.
-	RecordedByEmail string `json:"recorded_by_email"`
-
-	// Any notes associated with the payment.
-	Notes string `json:"notes"`
-
-	// Either the card authorization or PayPal transaction ID.
-	TransactionID string `json:"transaction_id"`
-
-	// The payment gateway id and name used to process the payment.
-	PaymentGateway PaymentGateway `json:"payment_gateway"`
-
-	// Date and time the payment was recorded.
-	CreatedAt time.Time `json:"created_at"`
-
-	// Date and time the payment was last updated.
-	UpdatedAt time.Time `json:"updated_at"`
-
-	Hv *Client `json:"-"`
-}
-
-type PaymentGateway struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
-}
-
 type Result[T any] struct {
 	client *Client
 }
 
-func New(accountID int64, token string) (*Client, error) {
-	cookieJar, err := cookiejar.New(nil)
-	if err != nil {
-		return nil, err
+func New(accountID int64, token string, opts ...ClientOption) (*Client, error) {
+	hv := &Client{
+		accountID: accountID,
+		token:     token,
+		baseURL:   defaultBaseURL,
+		userAgent: defaultUserAgent,
+		bucket:    ratelimit.NewBucket(15*time.Second/100, 100),
+		retry:     defaultRetryPolicy,
 	}
+	for _, o := range opts {
+		o(hv)
+	}
+
+	if hv.client == nil {
+		cookieJar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
 
-	client := &http.Client{
-		Jar: cookieJar,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
+		hv.client = &http.Client{
+			Jar: cookieJar,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
 	}
 
-	return &Client{
-		accountID: accountID,
-		token:     token,
-		client:    client,
-		bucket:    ratelimit.NewBucket(15*time.Second/100, 100),
-	}, nil
+	return hv, nil
 }
 
-func (hv *Client) GetCompanyInfo() (*Company, error) {
+func (hv *Client) GetCompanyInfo(ctx context.Context) (*Company, error) {
+	hv.companyMu.Lock()
+	defer hv.companyMu.Unlock()
+
 	if hv.company != nil {
 		return hv.company, nil
 	}
 
-	req, err := http.NewRequest("GET", serverUrl+"/company", nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
-
-	hv.bucket.Wait(1)
-	resp, err := hv.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Failed to load company info: %d", resp.StatusCode)
-	}
-
-	info := &Company{}
-	err = json.NewDecoder(resp.Body).Decode(info)
+	info, err := doJSON[Company](ctx, hv, "GET", hv.baseURL+"/company", nil, http.StatusOK, "load company info")
 	if err != nil {
 		return nil, err
 	}
@@ -254,359 +203,453 @@ func (hv *Client) GetCompanyInfo() (*Company, error) {
 	return info, nil
 }
 
-func (hv *Client) Invoices(opts ...requestOption) iter.Seq2[*Invoice, error] {
-	return fetchIter[Invoice](hv, "invoices", "invoices", opts)
+func (hv *Client) Invoices(ctx context.Context, opts ...InvoiceOption) iter.Seq2[*Invoice, error] {
+	return fetchIter[Invoice](ctx, hv, "invoices", "invoices", opts)
 }
 
-func (hv *Client) Customers(opts ...requestOption) iter.Seq2[*Customer, error] {
-	return fetchIter[Customer](hv, "customers", "customers", opts)
+func fetchIter[T any, O requestOption](ctx context.Context, hv *Client, field, path string, opts []O) iter.Seq2[*T, error] {
+	return newPager[T](hv, field, path, opts).All(ctx)
 }
 
-func (hv *Client) Expenses(opts ...requestOption) iter.Seq2[*Expense, error] {
-	return fetchIter[Expense](hv, "expenses", "expenses", opts)
+func fetchAll[T any](ctx context.Context, hv *Client, url, field string) ([]*T, string, error) {
+	p, err := fetchPage[T](ctx, hv, url, field)
+	if err != nil {
+		return nil, "", err
+	}
+	return p.Items, p.Links.Next, nil
 }
 
-func fetchIter[T any](hv *Client, field, path string, opts []requestOption) iter.Seq2[*T, error] {
-	v := &url.Values{}
-	for _, o := range opts {
-		o(v)
-	}
-	url := fmt.Sprintf("%s/%s?%s", serverUrl, path, v.Encode())
-
-	var buf []*T
-	return func(yield func(*T, error) bool) {
-		for {
-			if len(buf) == 0 && url != "" {
-				items, next, err := fetchAll[T](hv, url, field)
-				if err != nil {
-					if !yield(nil, err) {
-						return
-					}
-				}
-				buf = items
-				url = next
-			}
-
-			if len(buf) == 0 {
-				return
-			}
-
-			obj := buf[0]
-			buf = buf[1:]
+// page is a page of a listing, along with the pagination details Harvest
+// sends.
+type page[T any] struct {
+	Items        []*T
+	Page         int
+	TotalPages   int
+	TotalEntries int
 
-			if !yield(obj, nil) {
-				return
-			}
-		}
+	Links struct {
+		Next string `json:"next"`
 	}
 }
 
-func fetchAll[T any](hv *Client, url, field string) ([]*T, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func fetchPage[T any](ctx context.Context, hv *Client, url, field string) (*page[T], error) {
+	r, err := doJSON[map[string]json.RawMessage](ctx, hv, "GET", url, nil, http.StatusOK, "load "+url)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
 
-	hv.bucket.Wait(1)
-	resp, err := hv.client.Do(req)
-	if err != nil {
-		return nil, "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("Failed to load %s: %d", url, resp.StatusCode)
+	result := &page[T]{}
+	for k, v := range map[string]any{
+		"page":          &result.Page,
+		"total_pages":   &result.TotalPages,
+		"total_entries": &result.TotalEntries,
+		"links":         &result.Links,
+	} {
+		raw, ok := (*r)[k]
+		if !ok {
+			continue
+		}
+		err = json.Unmarshal(raw, v)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	r := make(map[string]json.RawMessage)
+	_, ok := (*r)[field]
+	if !ok {
+		return nil, fmt.Errorf("Missing field: %s", field)
+	}
 
-	err = json.NewDecoder(resp.Body).Decode(&r)
+	err = json.Unmarshal((*r)[field], &result.Items)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	var links struct {
-		Next string `json:"next"`
+	for _, obj := range result.Items {
+		setClient(hv, obj)
 	}
 
-	err = json.Unmarshal(r["links"], &links)
+	return result, nil
+}
+
+func (hv *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-type", "application/json")
 	}
+	hv.authorize(req)
+	return req, nil
+}
 
-	_, ok := r[field]
-	if !ok {
-		return nil, "", fmt.Errorf("Missing field: %s", field)
+// authorize adds the credentials to req.
+func (hv *Client) authorize(req *http.Request) {
+	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
+}
+
+// isHarvestURL reports whether u points to the API or to the Harvest account
+// of the company, the only places the credentials may be sent to.
+func (hv *Client) isHarvestURL(ctx context.Context, u *url.URL) (bool, error) {
+	sameHost := func(base string) bool {
+		b, err := url.Parse(base)
+		return err == nil && b.Scheme == u.Scheme && b.Host == u.Host && u.User == nil
+	}
+	if sameHost(hv.baseURL) {
+		return true, nil
 	}
 
-	var results []*T
-	err = json.Unmarshal(r[field], &results)
+	info, err := hv.GetCompanyInfo(ctx)
 	if err != nil {
-		return nil, "", err
+		return false, err
 	}
+	return sameHost(info.BaseURI), nil
+}
 
-	c := reflect.ValueOf(hv)
-	for _, obj := range results {
-		v := reflect.ValueOf(obj).Elem()
-		v.FieldByName("Hv").Set(c)
+// wait blocks until the rate limiter allows another request or ctx is done.
+func (hv *Client) wait(ctx context.Context) error {
+	if hv.bucket == nil {
+		return ctx.Err()
 	}
-
-	return results, links.Next, nil
+	return sleep(ctx, hv.bucket.Take(1))
 }
 
-func (hv *Client) FetchCustomers(opts ...requestOption) ([]*Customer, error) {
-	v := &url.Values{}
-	for _, o := range opts {
-		o(v)
+// do sends req, respecting the rate limit and retrying according to the retry
+// policy.
+func (hv *Client) do(req *http.Request) (*http.Response, error) {
+	if hv.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", hv.userAgent)
 	}
-	result, _, err := fetchAll[Customer](hv, fmt.Sprintf("%s/customers?%s", serverUrl, v.Encode()), "customers")
-	return result, err
-}
 
-func (hv *Client) FetchInvoices(opts ...requestOption) ([]*Invoice, error) {
-	v := &url.Values{}
-	for _, o := range opts {
-		o(v)
+	// Streamed bodies can't be sent twice.
+	canRetry := req.Body == nil || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		err := hv.wait(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := hv.client.Do(req)
+		if !canRetry || attempt >= hv.retry.MaxRetries {
+			return resp, err
+		}
+
+		retry, delay := shouldRetry(req, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			resp.Body.Close()
+		}
+
+		err = sleep(req.Context(), max(delay, hv.retry.backoff(attempt)))
+		if err != nil {
+			return nil, err
+		}
 	}
-	result, _, err := fetchAll[Invoice](hv, fmt.Sprintf("%s/invoices?%s", serverUrl, v.Encode()), "invoices")
-	return result, err
 }
 
-func (hv *Client) GetInvoice(id int64) (*Invoice, error) {
-	url := fmt.Sprintf("%s/invoices/%d", serverUrl, id)
+// send issues a request with body (if any) encoded as JSON. The status code
+// must match expect, action is used in the error message. The caller is
+// responsible for closing the response body.
+func (hv *Client) send(ctx context.Context, method, url string, body any, expect int, action string) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := hv.newRequest(ctx, method, url, r)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
 
-	hv.bucket.Wait(1)
-	resp, err := hv.client.Do(req)
+	resp, err := hv.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Failed to load %s: %d", url, resp.StatusCode)
+	if resp.StatusCode != expect {
+		defer resp.Body.Close()
+		return nil, newAPIError(resp, action)
 	}
+	return resp, nil
+}
 
-	r := Invoice{}
-
-	err = json.NewDecoder(resp.Body).Decode(&r)
+// call is like send, but discards the response.
+func (hv *Client) call(ctx context.Context, method, url string, body any, expect int, action string) error {
+	resp, err := hv.send(ctx, method, url, body, expect, action)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	r.Hv = hv
-	return &r, nil
+	resp.Body.Close()
+	return nil
 }
 
-func (hv *Client) GetRecipients(customer int64) ([]*Recipient, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/contacts?client_id=%d", serverUrl, customer), nil)
+// doJSON is like send, but decodes the response into a new T.
+func doJSON[T any](ctx context.Context, hv *Client, method, url string, body any, expect int, action string) (*T, error) {
+	resp, err := hv.send(ctx, method, url, body, expect, action)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
+	defer resp.Body.Close()
 
-	hv.bucket.Wait(1)
-	resp, err := hv.client.Do(req)
+	result := new(T)
+	err = json.NewDecoder(resp.Body).Decode(result)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Failed to load invoices: %d", resp.StatusCode)
-	}
 
-	var r struct {
-		Contacts []struct {
-			Email     string `json:"email"`
-			FirstName string `json:"first_name"`
-			LastName  string `json:"last_name"`
-		} `json:"contacts"`
-	}
+	setClient(hv, result)
+	return result, nil
+}
 
-	err = json.NewDecoder(resp.Body).Decode(&r)
-	if err != nil {
-		return nil, err
+// setClient fills in the Hv field of obj, if it has one.
+func setClient(hv *Client, obj any) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
 	}
-
-	result := make([]*Recipient, 0)
-	for _, c := range r.Contacts {
-		result = append(result, &Recipient{
-			Email: c.Email,
-			Name:  fmt.Sprintf("%s %s", c.FirstName, c.LastName),
-		})
+	f := v.Elem().FieldByName("Hv")
+	if f.IsValid() && f.CanSet() {
+		f.Set(reflect.ValueOf(hv))
 	}
-	return result, nil
 }
 
-type createMessageRequest struct {
-	Recipients  []*Recipient `json:"recipients"`
-	SendCopy    bool         `json:"send_me_a_copy"`
-	IncludeLink bool         `json:"include_link_to_client_invoice"`
-	AttachPDF   bool         `json:"attach_pdf"`
-	Subject     string       `json:"subject"`
-	Body        string       `json:"body"`
-}
-
-func (i *Invoice) Send(subject, body string, to []*Recipient) error {
-	data, err := json.Marshal(createMessageRequest{
-		Recipients:  to,
-		SendCopy:    true,
-		IncludeLink: true,
-		AttachPDF:   true,
-		Subject:     subject,
-		Body:        body,
-	})
-	if err != nil {
-		return err
+func (hv *Client) FetchInvoices(ctx context.Context, opts ...InvoiceOption) ([]*Invoice, error) {
+	v := &url.Values{}
+	for _, o := range opts {
+		o.apply(v)
 	}
+	result, _, err := fetchAll[Invoice](ctx, hv, fmt.Sprintf("%s/invoices?%s", hv.baseURL, v.Encode()), "invoices")
+	return result, err
+}
 
-	url := fmt.Sprintf("%s/invoices/%d/messages", serverUrl, i.ID)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+func (hv *Client) GetInvoice(ctx context.Context, id int64) (*Invoice, error) {
+	url := fmt.Sprintf("%s/invoices/%d", hv.baseURL, id)
+	return doJSON[Invoice](ctx, hv, "GET", url, nil, http.StatusOK, "load "+url)
+}
+
+// UpdateInvoice holds the fields to change on an invoice, nil and zero values
+// are left untouched.
+//
+// Line items with an ID are updated (or removed when Destroy is set), those
+// without are added.
+type UpdateInvoice struct {
+	ClientID       int64       `json:"client_id,omitempty"`
+	Number         string      `json:"number,omitempty"`
+	PurchaseOrder  *string     `json:"purchase_order,omitempty"`
+	Tax            *float64    `json:"tax,omitempty"`
+	Tax2           *float64    `json:"tax2,omitempty"`
+	Discount       *float64    `json:"discount,omitempty"`
+	Subject        *string     `json:"subject,omitempty"`
+	Notes          *string     `json:"notes,omitempty"`
+	Currency       string      `json:"currency,omitempty"`
+	IssueDate      string      `json:"issue_date,omitempty"`
+	DueDate        string      `json:"due_date,omitempty"`
+	PaymentTerm    string      `json:"payment_term,omitempty"`
+	PaymentOptions []string    `json:"payment_options,omitempty"`
+	LineItems      []*LineItem `json:"line_items,omitempty"`
+}
+
+// Update changes the invoice and updates i with the result.
+func (i *Invoice) Update(ctx context.Context, u *UpdateInvoice) error {
+	err := i.Hv.checkKinds(ctx, u.LineItems)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-type", "application/json")
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(i.Hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", i.Hv.token))
 
-	i.Hv.bucket.Wait(1)
-	resp, err := i.Hv.client.Do(req)
+	url := fmt.Sprintf("%s/invoices/%d", i.Hv.baseURL, i.ID)
+	r, err := doJSON[Invoice](ctx, i.Hv, "PATCH", url, u, http.StatusOK, "update invoice")
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Failed to send invoice: %d", resp.StatusCode)
-	}
 
+	*i = *r
 	return nil
 }
 
-type markSentRequest struct {
-	EventType string `json:"event_type"`
+func (i *Invoice) Delete(ctx context.Context) error {
+	url := fmt.Sprintf("%s/invoices/%d", i.Hv.baseURL, i.ID)
+	return i.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete invoice")
 }
 
-func (i *Invoice) MarkSent() error {
-	data, err := json.Marshal(markSentRequest{
-		EventType: "send",
-	})
-	if err != nil {
-		return err
+// AddLineItems adds line items to the invoice.
+func (i *Invoice) AddLineItems(ctx context.Context, items ...*LineItem) error {
+	for _, li := range items {
+		if li.ID != 0 {
+			return fmt.Errorf("Line item already exists: %d", li.ID)
+		}
 	}
+	return i.Update(ctx, &UpdateInvoice{LineItems: items})
+}
 
-	url := fmt.Sprintf("%s/invoices/%d/messages", serverUrl, i.ID)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
-	if err != nil {
-		return err
+// UpdateLineItems changes existing line items, matched by ID.
+func (i *Invoice) UpdateLineItems(ctx context.Context, items ...*LineItem) error {
+	for _, li := range items {
+		if li.ID == 0 {
+			return fmt.Errorf("Missing line item ID")
+		}
 	}
-	req.Header.Set("Content-type", "application/json")
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(i.Hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", i.Hv.token))
+	return i.Update(ctx, &UpdateInvoice{LineItems: items})
+}
 
-	i.Hv.bucket.Wait(1)
-	resp, err := i.Hv.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Failed to mark invoice as sent: %d", resp.StatusCode)
+// RemoveLineItems removes the line items with the given IDs.
+func (i *Invoice) RemoveLineItems(ctx context.Context, ids ...int64) error {
+	items := make([]*LineItem, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, &LineItem{ID: id, Destroy: true})
 	}
-
-	return nil
+	return i.Update(ctx, &UpdateInvoice{LineItems: items})
 }
 
-type createPaymentRequest struct {
-	Amount   float64 `json:"amount"`
-	PaidDate string  `json:"paid_date"`
-	Notes    string  `json:"notes"`
+type createMessageRequest struct {
+	Recipients     []*Recipient `json:"recipients"`
+	SendCopy       bool         `json:"send_me_a_copy"`
+	IncludeLink    bool         `json:"include_link_to_client_invoice"`
+	AttachPDF      bool         `json:"attach_pdf"`
+	ThankYou       bool         `json:"thank_you,omitempty"`
+	Reminder       bool         `json:"reminder,omitempty"`
+	SendReminderOn string       `json:"send_reminder_on,omitempty"`
+	Subject        string       `json:"subject"`
+	Body           string       `json:"body"`
+}
+
+// SendOptions controls how an invoice gets sent.
+type SendOptions struct {
+	// Extra recipients, Harvest treats these like any other recipient.
+	CC []*Recipient
+
+	// Whether to email a copy of the message to the current user.
+	SendMeACopy bool
+
+	// Whether to include a link to the client invoice in the message body.
+	IncludeLink bool
+
+	// Whether to attach the invoice PDF to the message email.
+	AttachPDF bool
+
+	// Send a thank you message (for paid invoices).
+	ThankYou bool
+
+	// Send a reminder (for overdue invoices), optionally scheduled for a
+	// later date.
+	Reminder       bool
+	SendReminderOn string
+}
+
+// DefaultSendOptions returns the options used by Send when none are given: a
+// copy is sent to the current user, the message links to the client invoice
+// and has the PDF attached. Callers can change the result to start from these.
+func DefaultSendOptions() *SendOptions {
+	return &SendOptions{
+		SendMeACopy: true,
+		IncludeLink: true,
+		AttachPDF:   true,
+	}
 }
 
-func (i *Invoice) AddPayment(amount float64, date time.Time, notes string) error {
-	data, err := json.Marshal(createPaymentRequest{
-		Amount:   amount,
-		PaidDate: date.Format("2006-01-02"),
-		Notes:    notes,
-	})
-	if err != nil {
-		return err
+// Send emails the invoice to the given recipients and returns the created
+// message. When opts is nil, DefaultSendOptions are used.
+func (i *Invoice) Send(ctx context.Context, subject, body string, to []*Recipient, opts *SendOptions) (*InvoiceMessage, error) {
+	if opts == nil {
+		opts = DefaultSendOptions()
 	}
 
-	url := fmt.Sprintf("%s/invoices/%d/payments", serverUrl, i.ID)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-type", "application/json")
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(i.Hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", i.Hv.token))
+	recipients := make([]*Recipient, 0, len(to)+len(opts.CC))
+	recipients = append(recipients, to...)
+	recipients = append(recipients, opts.CC...)
 
-	i.Hv.bucket.Wait(1)
-	resp, err := i.Hv.client.Do(req)
+	url := fmt.Sprintf("%s/invoices/%d/messages", i.Hv.baseURL, i.ID)
+	m, err := doJSON[InvoiceMessage](ctx, i.Hv, "POST", url, createMessageRequest{
+		Recipients:     recipients,
+		SendCopy:       opts.SendMeACopy,
+		IncludeLink:    opts.IncludeLink,
+		AttachPDF:      opts.AttachPDF,
+		ThankYou:       opts.ThankYou,
+		Reminder:       opts.Reminder,
+		SendReminderOn: opts.SendReminderOn,
+		Subject:        subject,
+		Body:           body,
+	}, http.StatusCreated, "send invoice")
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Failed to add payment: %d", resp.StatusCode)
+
+	// Sending a draft opens it.
+	if i.State == string(InvoiceDraft) {
+		i.State = string(InvoiceOpen)
 	}
+	return m, nil
+}
 
-	return nil
+type eventRequest struct {
+	EventType string `json:"event_type"`
 }
 
-func (i *Invoice) Download() (io.ReadCloser, error) {
-	info, err := i.Hv.GetCompanyInfo()
+// MarkSent marks a draft invoice as sent, without emailing it.
+func (i *Invoice) MarkSent(ctx context.Context) error {
+	return i.event(ctx, "send", InvoiceOpen, "mark invoice as sent")
+}
+
+func (i *Invoice) Download(ctx context.Context) (io.ReadCloser, error) {
+	info, err := i.Hv.GetCompanyInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/client/invoices/%s.pdf", info.BaseURI, i.ClientKey)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	i.Hv.bucket.Wait(1)
-	resp, err := i.Hv.client.Do(req)
+	resp, err := i.Hv.do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("Failed to download PDF: %d", resp.StatusCode)
+		defer resp.Body.Close()
+		return nil, newAPIError(resp, "download PDF")
 	}
 	return resp.Body, nil
 }
 
-func (i *Invoice) GetAttachments() ([]*Attachment, error) {
-	info, err := i.Hv.GetCompanyInfo()
+func (i *Invoice) GetAttachments(ctx context.Context) ([]*Attachment, error) {
+	info, err := i.Hv.GetCompanyInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s/client/invoices/%s", info.BaseURI, i.ClientKey)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	i.Hv.bucket.Wait(1)
-	resp, err := i.Hv.client.Do(req)
+	resp, err := i.Hv.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Failed to fetch attachments: %d", resp.StatusCode)
+		return nil, newAPIError(resp, "fetch attachments")
 	}
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
@@ -626,161 +669,76 @@ func (i *Invoice) GetAttachments() ([]*Attachment, error) {
 	return result, nil
 }
 
-func (i *Invoice) GetPayments() ([]*Payment, error) {
-	result, _, err := fetchAll[Payment](i.Hv, fmt.Sprintf("%s/invoices/%d/payments", serverUrl, i.ID), "invoice_payments")
-	return result, err
-}
-
-func (a *Attachment) Download() (io.ReadCloser, error) {
-	info, err := a.hv.GetCompanyInfo()
+func (a *Attachment) Download(ctx context.Context) (io.ReadCloser, error) {
+	info, err := a.hv.GetCompanyInfo(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	url := fmt.Sprintf("%s%s", info.BaseURI, a.Path)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	a.hv.bucket.Wait(1)
-	resp, err := a.hv.client.Do(req)
+	resp, err := a.hv.do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		resp.Body.Close()
-		return nil, fmt.Errorf("Failed to download attachment: %d", resp.StatusCode)
+		defer resp.Body.Close()
+		return nil, newAPIError(resp, "download attachment")
 	}
 	return resp.Body, nil
 }
 
-func (hv *Client) FetchExpenses(opts ...requestOption) ([]*Expense, error) {
-	v := &url.Values{}
-	for _, o := range opts {
-		o(v)
+// CreateInvoice creates a new invoice and returns it. ClientID is required.
+//
+// Line items can be passed in directly, or imported from uninvoiced time
+// entries and expenses by setting LineItemsImport.
+func (hv *Client) CreateInvoice(ctx context.Context, invoice *Invoice) (*Invoice, error) {
+	err := hv.checkKinds(ctx, invoice.LineItems)
+	if err != nil {
+		return nil, err
 	}
-	result, _, err := fetchAll[Expense](hv, fmt.Sprintf("%s/expenses?%s", serverUrl, v.Encode()), "expenses")
-	return result, err
-}
-
-type CreateExpense struct {
-	ProjectID         int64
-	ExpenseCategoryID int64
-	SpentDate         string
-	TotalCost         float64
-	Notes             string
-
-	Filename    string
-	ContentType string
-	File        io.Reader
-}
-
-func (hv *Client) CreateExpense(e *CreateExpense) error {
-	pr, pw := io.Pipe()
-	mp := multipart.NewWriter(pw)
-
-	var g errgroup.Group
-	g.Go(func() error {
-		defer pw.Close()
-
-		for _, f := range []struct {
-			Field string
-			Value string
-		}{
-			{"spent_date", e.SpentDate},
-			{"project_id", strconv.FormatInt(e.ProjectID, 10)},
-			{"expense_category_id", strconv.FormatInt(e.ExpenseCategoryID, 10)},
-			{"notes", e.Notes},
-			{"total_cost", fmt.Sprintf("%g", e.TotalCost)},
-		} {
-			fw, err := mp.CreateFormField(f.Field)
-			if err != nil {
-				return err
-			}
-
-			_, err = io.WriteString(fw, f.Value)
-			if err != nil {
-				return err
-			}
-		}
-
-		if e.File != nil {
-			h := textproto.MIMEHeader{}
-			h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="receipt"; filename="%s"`, e.Filename))
-			h.Set("Content-Type", e.ContentType)
-
-			fw, err := mp.CreatePart(h)
-			if err != nil {
-				return err
-			}
 
-			_, err = io.Copy(fw, e.File)
-			if err != nil {
-				return err
-			}
-		}
-
-		return mp.Close()
-	})
-	g.Go(func() (err error) {
-		defer func() {
-			if err != nil {
-				_, _ = io.Copy(io.Discard, pr)
-			}
-		}()
+	url := fmt.Sprintf("%s/invoices", hv.baseURL)
+	return doJSON[Invoice](ctx, hv, "POST", url, invoice, http.StatusCreated, "create invoice")
+}
 
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/expenses", serverUrl), pr)
-		if err != nil {
-			return err
-		}
-		req.Header.Set("Content-Type", mp.FormDataContentType())
-		req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
+// LineItemsImport creates invoice line items from the uninvoiced time and
+// expenses of a set of projects.
+type LineItemsImport struct {
+	// The projects to import from.
+	ProjectIDs []int64 `json:"project_ids"`
 
-		hv.bucket.Wait(1)
-		resp, err := hv.client.Do(req)
-		if err != nil {
-			return err
-		}
-		defer resp.Body.Close()
+	// Import time entries, if set.
+	Time *TimeImport `json:"time,omitempty"`
 
-		if resp.StatusCode != http.StatusCreated {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("Failed to create expense (%d): %s", resp.StatusCode, string(body))
-		}
+	// Import expenses, if set.
+	Expenses *ExpenseImport `json:"expenses,omitempty"`
+}
 
-		return nil
-	})
+type TimeImport struct {
+	// How to summarize the time entries per line item: project, task, people
+	// or detailed.
+	SummaryType string `json:"summary_type"`
 
-	return g.Wait()
+	// Start and end date of the time entries to import (both optional).
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
 }
 
-func (hv *Client) CreateInvoice(invoice *Invoice) error {
-	data, err := json.Marshal(invoice)
-	if err != nil {
-		return err
-	}
-
-	url := fmt.Sprintf("%s/invoices", serverUrl)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-type", "application/json")
-	req.Header.Set("Harvest-Account-ID", strconv.FormatInt(hv.accountID, 10))
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", hv.token))
+type ExpenseImport struct {
+	// How to summarize the expenses per line item: project, category, people
+	// or detailed.
+	SummaryType string `json:"summary_type"`
 
-	hv.bucket.Wait(1)
-	resp, err := hv.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("Failed to create invoice: %d", resp.StatusCode)
-	}
+	// Start and end date of the expenses to import (both optional).
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
 
-	return nil
+	// Whether to attach the expense receipts to the invoice.
+	AttachReceipt bool `json:"attach_receipt,omitempty"`
 }
@@ -1,13 +1,28 @@
-package harvest
+package harvest_test
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"io"
+	"iter"
 	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/rubenv/harvest"
+	"github.com/rubenv/harvest/harvesttest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,16 +35,17 @@ func TestFetchInvoices(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	ctx := context.Background()
 
 	accountID, err := strconv.ParseInt(testAccountID, 10, 64)
 	assert.NoError(err)
 	assert.True(accountID > 0)
 
-	hv, err := New(accountID, testToken)
+	hv, err := harvest.New(accountID, testToken)
 	assert.NoError(err)
 	assert.NotNil(hv)
 
-	inv, err := hv.FetchInvoices()
+	inv, err := hv.FetchInvoices(ctx)
 	assert.NoError(err)
 	assert.True(len(inv) > 0)
 
@@ -40,11 +56,11 @@ func TestFetchInvoices(t *testing.T) {
 	i := inv[2]
 	assert.True(len(i.LineItems) > 0)
 
-	r, err := hv.GetRecipients(i.Customer.ID)
+	r, err := hv.GetRecipients(ctx, i.Customer.ID)
 	assert.NoError(err)
 	assert.True(len(r) > 0)
 
-	rc, err := i.Download()
+	rc, err := i.Download(ctx)
 	assert.NoError(err)
 	assert.NotNil(rc)
 	defer rc.Close()
@@ -60,16 +76,17 @@ func TestFetchExpenses(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	ctx := context.Background()
 
 	accountID, err := strconv.ParseInt(testAccountID, 10, 64)
 	assert.NoError(err)
 	assert.True(accountID > 0)
 
-	hv, err := New(accountID, testToken)
+	hv, err := harvest.New(accountID, testToken)
 	assert.NoError(err)
 	assert.NotNil(hv)
 
-	exp, err := hv.FetchExpenses()
+	exp, err := hv.FetchExpenses(ctx)
 	assert.NoError(err)
 	assert.True(len(exp) > 0)
 
@@ -84,19 +101,20 @@ func TestIterInvoices(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	ctx := context.Background()
 
 	accountID, err := strconv.ParseInt(testAccountID, 10, 64)
 	assert.NoError(err)
 	assert.True(accountID > 0)
 
-	hv, err := New(accountID, testToken)
+	hv, err := harvest.New(accountID, testToken)
 	assert.NoError(err)
 	assert.NotNil(hv)
 
 	total := 0
 	found := false
 	found2 := false
-	for inv, err := range hv.Invoices() {
+	for inv, err := range hv.Invoices(ctx) {
 		assert.NoError(err)
 		total += 1
 		log.Printf("%s (%s) -> %s (%s -> %s)", inv.Number, inv.Customer.Name, inv.State, inv.IssueDate, inv.SentAt)
@@ -105,11 +123,11 @@ func TestIterInvoices(t *testing.T) {
 		if inv.ID == 38903909 {
 			found = true
 
-			a, err := inv.GetAttachments()
+			a, err := inv.GetAttachments(ctx)
 			assert.NoError(err)
 			assert.True(len(a) > 0)
 
-			rc, err := a[0].Download()
+			rc, err := a[0].Download(ctx)
 			assert.NoError(err)
 			assert.NotNil(rc)
 			defer rc.Close()
@@ -120,7 +138,7 @@ func TestIterInvoices(t *testing.T) {
 		}
 
 		if inv.ID == 48542679 {
-			p, err := inv.GetPayments()
+			p, err := inv.GetPayments(ctx)
 			assert.NoError(err)
 			assert.NotNil(p)
 
@@ -137,16 +155,704 @@ func TestGetInvoice(t *testing.T) {
 	}
 
 	assert := assert.New(t)
+	ctx := context.Background()
 
 	accountID, err := strconv.ParseInt(testAccountID, 10, 64)
 	assert.NoError(err)
 	assert.True(accountID > 0)
 
-	hv, err := New(accountID, testToken)
+	hv, err := harvest.New(accountID, testToken)
 	assert.NoError(err)
 	assert.NotNil(hv)
 
-	inv, err := hv.GetInvoice(48542679)
+	inv, err := hv.GetInvoice(ctx, 48542679)
 	assert.NoError(err)
 	assert.NotNil(inv)
 }
+
+func TestIterTimeEntries(t *testing.T) {
+	if testAccountID == "" || testToken == "" {
+		t.SkipNow()
+	}
+
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	accountID, err := strconv.ParseInt(testAccountID, 10, 64)
+	assert.NoError(err)
+	assert.True(accountID > 0)
+
+	hv, err := harvest.New(accountID, testToken)
+	assert.NoError(err)
+	assert.NotNil(hv)
+
+	for e, err := range hv.TimeEntries(ctx) {
+		assert.NoError(err)
+		log.Printf("%s (%s) -> %s (%g)", e.SpentDate, e.Project.Name, e.Notes, e.Hours)
+		assert.NotNil(e.Hv)
+
+		r, err := hv.GetTimeEntry(ctx, e.ID)
+		assert.NoError(err)
+		assert.Equal(e.ID, r.ID)
+		break
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	assert := assert.New(t)
+
+	hv, err := harvest.New(1, "token")
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = hv.GetInvoice(ctx, 1)
+	assert.ErrorIs(err, context.Canceled)
+
+	for _, err := range hv.Invoices(ctx) {
+		assert.ErrorIs(err, context.Canceled)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/v2/company", r.URL.Path)
+		assert.Equal("test-agent", r.Header.Get("User-Agent"))
+		assert.Equal("1", r.Header.Get("Harvest-Account-ID"))
+		assert.Equal("Bearer token", r.Header.Get("Authorization"))
+		_, _ = io.WriteString(w, `{"name": "Test company"}`)
+	}))
+	defer srv.Close()
+
+	hv, err := harvest.New(1, "token",
+		harvest.WithBaseURL(srv.URL+"/v2/"),
+		harvest.WithHTTPClient(srv.Client()),
+		harvest.WithUserAgent("test-agent"),
+		harvest.WithRateLimit(0, 0),
+	)
+	assert.NoError(err)
+
+	info, err := hv.GetCompanyInfo(context.Background())
+	assert.NoError(err)
+	assert.Equal("Test company", info.Name)
+}
+
+func TestRequestOptions(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = io.WriteString(w, `{"time_entries": [], "invoices": [], "projects": [], "links": {}}`)
+	}))
+	defer srv.Close()
+
+	hv, err := harvest.New(1, "token", harvest.WithBaseURL(srv.URL))
+	assert.NoError(err)
+
+	since := time.Date(2024, 1, 2, 13, 4, 5, 0, time.FixedZone("CET", 3600))
+	for range hv.TimeEntries(ctx,
+		harvest.WithClientID(1),
+		harvest.WithProjectID(2),
+		harvest.WithUserID(3),
+		harvest.WithTaskID(4),
+		harvest.WithIsBilled(false),
+		harvest.WithIsRunning(true),
+		harvest.WithApprovalStatus(harvest.ApprovalApproved),
+		harvest.WithFrom(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		harvest.WithTo(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)),
+		harvest.WithUpdatedSince(since),
+		harvest.WithPerPage(50),
+	) {
+	}
+
+	assert.Equal(url.Values{
+		"client_id":       {"1"},
+		"project_id":      {"2"},
+		"user_id":         {"3"},
+		"task_id":         {"4"},
+		"is_billed":       {"false"},
+		"is_running":      {"true"},
+		"approval_status": {"approved"},
+		"from":            {"2024-01-01"},
+		"to":              {"2024-01-31"},
+		"updated_since":   {"2024-01-02T12:04:05Z"},
+		"per_page":        {"50"},
+	}, query)
+
+	for range hv.Invoices(ctx, harvest.WithInvoiceState(harvest.InvoiceOpen)) {
+	}
+	assert.Equal(url.Values{"state": {"open"}}, query)
+
+	for range hv.Projects(ctx, harvest.WithIsActive(true)) {
+	}
+	assert.Equal(url.Values{"is_active": {"true"}}, query)
+}
+
+func TestOptionTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	// Options only fit the listings that support them
+	_, ok := any(harvest.WithIsRunning(true)).(harvest.InvoiceOption)
+	assert.False(ok)
+	_, ok = any(harvest.WithThankYou()).(harvest.ActiveOption)
+	assert.False(ok)
+	_, ok = any(harvest.WithInvoiceState(harvest.InvoicePaid)).(harvest.ListOption)
+	assert.False(ok)
+	_, ok = any(harvest.WithEstimateState(harvest.EstimateSent)).(harvest.InvoiceOption)
+	assert.False(ok)
+	_, ok = any(harvest.WithUpdatedSince(time.Now())).(harvest.PageOption)
+	assert.False(ok)
+
+	_, ok = any(harvest.WithClientID(1)).(harvest.InvoiceOption)
+	assert.True(ok)
+	_, ok = any(harvest.WithIsActive(true)).(harvest.ActiveOption)
+	assert.True(ok)
+	_, ok = any(harvest.WithPerPage(10)).(harvest.PageOption)
+	assert.True(ok)
+}
+
+func TestFilters(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddProject(&harvest.Project{Customer: &harvest.Customer{ID: 1}, Name: "Active", IsActive: true})
+	srv.AddProject(&harvest.Project{Customer: &harvest.Customer{ID: 1}, Name: "Archived", IsActive: false})
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, State: "draft", IssueDate: "2024-01-05"})
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 2}, State: "open", IssueDate: "2024-02-05"})
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: 1}, User: &harvest.UserRef{ID: 1}, SpentDate: "2024-01-01", Hours: 1, IsBilled: true})
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: 1}, User: &harvest.UserRef{ID: 2}, SpentDate: "2024-01-02", Hours: 2})
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: 2}, User: &harvest.UserRef{ID: 2}, SpentDate: "2024-02-01", Hours: 4})
+
+	count := func(seq iter.Seq2[*harvest.TimeEntry, error]) float64 {
+		hours := 0.0
+		for e, err := range seq {
+			assert.NoError(err)
+			hours += e.Hours
+		}
+		return hours
+	}
+	assert.Equal(7.0, count(hv.TimeEntries(ctx)))
+	assert.Equal(3.0, count(hv.TimeEntries(ctx, harvest.WithProjectID(1))))
+	assert.Equal(6.0, count(hv.TimeEntries(ctx, harvest.WithUserID(2))))
+	assert.Equal(6.0, count(hv.TimeEntries(ctx, harvest.WithIsBilled(false))))
+	assert.Equal(2.0, count(hv.TimeEntries(ctx, harvest.WithUserID(2), harvest.WithTo(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))))
+	assert.Equal(4.0, count(hv.TimeEntries(ctx, harvest.WithFrom(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)))))
+	assert.Equal(7.0, count(hv.TimeEntries(ctx, harvest.WithApprovalStatus(harvest.ApprovalUnsubmitted))))
+	assert.Equal(7.0, count(hv.TimeEntries(ctx, harvest.WithPerPage(1))))
+
+	for p, err := range hv.Projects(ctx, harvest.WithIsActive(false)) {
+		assert.NoError(err)
+		assert.Equal("Archived", p.Name)
+	}
+
+	invoices, err := hv.FetchInvoices(ctx, harvest.WithInvoiceState(harvest.InvoiceOpen))
+	assert.NoError(err)
+	if assert.Len(invoices, 1) {
+		assert.Equal("2024-02-05", invoices[0].IssueDate)
+	}
+	invoices, err = hv.FetchInvoices(ctx, harvest.WithClientID(1))
+	assert.NoError(err)
+	if assert.Len(invoices, 1) {
+		assert.Equal("draft", invoices[0].State)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/invoices/1":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error": "not_found", "error_description": "The resource you requested could not be found"}`)
+		case "/invoices/3":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = io.WriteString(w, `{"message": "bad", "url": "spoofed", "statuscode": 200, "StatusCode": 200, "Method": "PUT", "RetryAfter": 5, "Body": "AA=="}`)
+		default:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	hv, err := harvest.New(1, "token", harvest.WithBaseURL(srv.URL), harvest.WithRetryPolicy(harvest.RetryPolicy{}))
+	assert.NoError(err)
+
+	_, err = hv.GetInvoice(context.Background(), 1)
+	assert.True(harvest.IsNotFound(err))
+	assert.False(harvest.IsRateLimited(err))
+
+	var apiErr *harvest.APIError
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal("GET", apiErr.Method)
+	assert.Equal(srv.URL+"/invoices/1", apiErr.URL)
+	assert.Equal("not_found", apiErr.Code)
+	assert.Equal("Failed to load "+srv.URL+"/invoices/1: 404: The resource you requested could not be found", err.Error())
+
+	_, err = hv.GetInvoice(context.Background(), 2)
+	assert.True(harvest.IsRateLimited(err))
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(7*time.Second, apiErr.RetryAfter)
+
+	// The body can't override the response details
+	_, err = hv.GetInvoice(context.Background(), 3)
+	assert.True(harvest.IsValidationError(err))
+	assert.ErrorAs(err, &apiErr)
+	assert.Equal(http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal("GET", apiErr.Method)
+	assert.Equal(srv.URL+"/invoices/3", apiErr.URL)
+	assert.Equal(time.Duration(0), apiErr.RetryAfter)
+	assert.Equal("bad", apiErr.Message)
+	assert.Contains(string(apiErr.Body), "spoofed")
+}
+
+func TestRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method+" "+r.URL.Path]++
+		n := calls[r.Method+" "+r.URL.Path]
+
+		switch {
+		case r.URL.Path == "/invoices/1" && n < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/invoices/1":
+			_, _ = io.WriteString(w, `{"id": 1}`)
+		case r.URL.Path == "/invoices" && n == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/invoices":
+			body, _ := io.ReadAll(r.Body)
+			assert.Contains(string(body), `"subject":"Test"`)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"id": 5}`)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	hv, err := harvest.New(1, "token", harvest.WithBaseURL(srv.URL), harvest.WithRetryPolicy(harvest.RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	}))
+	assert.NoError(err)
+
+	ctx := context.Background()
+
+	// Idempotent requests are retried on 5xx
+	inv, err := hv.GetInvoice(ctx, 1)
+	assert.NoError(err)
+	assert.Equal(int64(1), inv.ID)
+	assert.Equal(3, calls["GET /invoices/1"])
+
+	// Rate limited POSTs are retried, with the body intact
+	inv, err = hv.CreateInvoice(ctx, &harvest.Invoice{Subject: "Test"})
+	assert.NoError(err)
+	assert.Equal(int64(5), inv.ID)
+	assert.Equal(2, calls["POST /invoices"])
+
+	// Other POSTs are not
+	err = (&harvest.Invoice{ID: 2, Hv: hv}).MarkSent(ctx)
+	assert.Error(err)
+	assert.Equal(1, calls["POST /invoices/2/messages"])
+
+	// Gives up after MaxRetries
+	_, err = hv.GetInvoice(ctx, 3)
+	assert.Error(err)
+	assert.Equal(4, calls["GET /invoices/3"])
+}
+
+func newFake(t *testing.T) (*harvesttest.Server, *harvest.Client) {
+	srv := harvesttest.NewServer()
+	t.Cleanup(srv.Close)
+
+	hv, err := srv.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv, hv
+}
+
+// roundTripFunc fails every request with the error it returns.
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// timeoutError is a network timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestRetryNetworkErrors(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	badCert := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+
+	for _, c := range []struct {
+		name  string
+		err   error
+		post  bool
+		tries int
+	}{
+		{"timeout", timeoutError{}, false, 4},
+		{"refused", refused, false, 4},
+		{"refused post", refused, true, 4},
+		{"reset", reset, false, 4},
+		{"reset post", reset, true, 1},
+		{"eof", io.ErrUnexpectedEOF, false, 4},
+		{"bad certificate", badCert, false, 1},
+		{"hostname", x509.HostnameError{Host: "example.com"}, false, 1},
+		{"other", errors.New("unsupported protocol scheme"), false, 1},
+	} {
+		tries := 0
+		client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			tries++
+			return nil, c.err
+		})}
+		hv, err := harvest.New(1, "token", harvest.WithHTTPClient(client), harvest.WithRetryPolicy(harvest.RetryPolicy{
+			MaxRetries: 3,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+		}))
+		assert.NoError(err)
+
+		if c.post {
+			_, err = hv.CreateInvoice(ctx, &harvest.Invoice{Subject: "Test"})
+		} else {
+			_, err = hv.GetInvoice(ctx, 1)
+		}
+		assert.Error(err, c.name)
+		assert.Equal(c.tries, tries, c.name)
+	}
+
+	// Unsupported schemes fail right away
+	hv, err := harvest.New(1, "token", harvest.WithBaseURL("ftp://example.com"), harvest.WithRetryPolicy(harvest.RetryPolicy{
+		MaxRetries: 3,
+		MinBackoff: time.Second,
+	}))
+	assert.NoError(err)
+	start := time.Now()
+	_, err = hv.GetInvoice(ctx, 1)
+	assert.Error(err)
+	assert.Less(time.Since(start), time.Second)
+}
+
+func TestInvoices(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+	srv.PerPage = 2
+
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	other := &harvest.Customer{Name: "Other"}
+	srv.AddCustomer(other)
+	srv.AddContact(&harvest.Contact{Customer: acme, FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"})
+
+	for i := 0; i < 5; i++ {
+		srv.AddInvoice(&harvest.Invoice{
+			Customer: acme,
+			LineItems: []*harvest.LineItem{
+				{Kind: "Service", Description: "Work", Quantity: 2, UnitPrice: 50},
+			},
+		})
+	}
+	srv.AddInvoice(&harvest.Invoice{Customer: other, Amount: 10})
+
+	// Follows pagination
+	total := 0
+	for inv, err := range hv.Invoices(ctx, harvest.WithClientID(acme.ID)) {
+		assert.NoError(err)
+		assert.Equal("ACME", inv.Customer.Name)
+		assert.Equal(100.0, inv.Amount)
+		assert.NotNil(inv.Hv)
+		total++
+	}
+	assert.Equal(5, total)
+
+	// Only reads the first page
+	inv, err := hv.FetchInvoices(ctx)
+	assert.NoError(err)
+	assert.Len(inv, 2)
+
+	customers, err := hv.FetchCustomers(ctx)
+	assert.NoError(err)
+	assert.Len(customers, 2)
+
+	i, err := hv.GetInvoice(ctx, inv[1].ID)
+	assert.NoError(err)
+	assert.Equal("draft", i.State)
+	assert.Len(i.LineItems, 1)
+
+	r, err := hv.GetRecipients(ctx, acme.ID)
+	assert.NoError(err)
+	assert.Equal([]*harvest.Recipient{{Name: "Jane Doe", Email: "jane@example.com"}}, r)
+
+	m, err := i.Send(ctx, "Invoice", "Please pay", r, nil)
+	assert.NoError(err)
+	assert.True(m.AttachPDF)
+	assert.True(m.IncludeLinkToClientInvoice)
+	assert.True(m.SendMeACopy)
+	assert.Equal("open", srv.Invoice(i.ID).State)
+
+	assert.NoError(i.AddPayment(ctx, 100, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "Thanks"))
+	assert.Equal("paid", srv.Invoice(i.ID).State)
+
+	p, err := i.GetPayments(ctx)
+	assert.NoError(err)
+	assert.Len(p, 1)
+	assert.Equal("2024-01-01", p[0].PaidDate)
+
+	created, err := hv.CreateInvoice(ctx, &harvest.Invoice{ClientID: other.ID, Subject: "New"})
+	assert.NoError(err)
+	assert.Equal("Other", created.Customer.Name)
+	assert.NotEmpty(created.Number)
+	assert.NotEmpty(created.ClientKey)
+	assert.NotNil(created.Hv)
+	assert.Equal(created.ClientKey, srv.Invoice(created.ID).ClientKey)
+
+	_, err = hv.CreateInvoice(ctx, &harvest.Invoice{Subject: "No client"})
+	assert.True(harvest.IsValidationError(err))
+}
+
+func TestInvoiceDownloads(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	inv := &harvest.Invoice{Customer: &harvest.Customer{ID: 1, Name: "ACME"}}
+	srv.AddInvoice(inv)
+	srv.SetInvoicePDF(inv.ID, []byte("%PDF-1.4 invoice"))
+	srv.AddAttachment(inv.ID, "timesheet.pdf", []byte("%PDF-1.4 timesheet"))
+
+	i, err := hv.GetInvoice(ctx, inv.ID)
+	assert.NoError(err)
+
+	rc, err := i.Download(ctx)
+	assert.NoError(err)
+	data, err := io.ReadAll(rc)
+	assert.NoError(err)
+	assert.NoError(rc.Close())
+	assert.Equal("%PDF-1.4 invoice", string(data))
+
+	a, err := i.GetAttachments(ctx)
+	assert.NoError(err)
+	assert.Len(a, 1)
+	assert.Equal("timesheet.pdf", a[0].Filename)
+
+	rc, err = a[0].Download(ctx)
+	assert.NoError(err)
+	data, err = io.ReadAll(rc)
+	assert.NoError(err)
+	assert.NoError(rc.Close())
+	assert.Equal("%PDF-1.4 timesheet", string(data))
+}
+
+func TestExpenses(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddExpense(&harvest.Expense{Project: &harvest.Project{ID: 1, Name: "Project"}, SpentDate: "2024-01-01", TotalCost: 10})
+
+	e, err := hv.CreateExpense(ctx, &harvest.CreateExpense{
+		ProjectID:         1,
+		ExpenseCategoryID: 2,
+		SpentDate:         "2024-01-02",
+		TotalCost:         12.5,
+		Notes:             "Lunch",
+		Filename:          "receipt.pdf",
+		ContentType:       "application/pdf",
+		File:              strings.NewReader("%PDF-1.4 receipt"),
+	})
+	assert.NoError(err)
+	assert.Equal("Lunch", e.Notes)
+	assert.Equal("receipt.pdf", e.Receipt.FileName)
+
+	exp, err := hv.FetchExpenses(ctx)
+	assert.NoError(err)
+	assert.Len(exp, 2)
+	assert.Equal("Lunch", exp[0].Notes)
+	assert.Equal(12.5, exp[0].TotalCost)
+}
+
+func TestCompanyInfoConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, _ := newFake(t)
+
+	transport := &slowTransport{}
+	hv, err := srv.Client(harvest.WithHTTPClient(&http.Client{Transport: transport}))
+	assert.NoError(err)
+
+	// Loaded once, no matter how many callers
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := hv.GetCompanyInfo(ctx)
+			assert.NoError(err)
+			assert.Equal(srv.URL, info.BaseURI)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(1, transport.requests)
+}
+
+func TestFaults(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	info, err := hv.GetCompanyInfo(ctx)
+	assert.NoError(err)
+	assert.Equal(srv.URL, info.BaseURI)
+
+	// Retried
+	srv.Inject(harvesttest.Fault{Path: "/invoices", Status: http.StatusServiceUnavailable, Times: 2})
+	_, err = hv.FetchInvoices(ctx)
+	assert.NoError(err)
+
+	srv.Inject(harvesttest.Fault{Path: "/invoices/1", Status: http.StatusForbidden, Code: "forbidden", Description: "Nope"})
+	_, err = hv.GetInvoice(ctx, 1)
+	assert.True(harvest.IsForbidden(err))
+	assert.ErrorContains(err, "Nope")
+
+	srv.Token = "other"
+	_, err = hv.FetchCustomers(ctx)
+	assert.True(harvest.IsUnauthorized(err))
+}
+
+func TestUpdateInvoice(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddInvoice(&harvest.Invoice{
+		Customer: &harvest.Customer{ID: 1, Name: "ACME"},
+		Subject:  "Invoice",
+		LineItems: []*harvest.LineItem{
+			{Kind: "Service", Description: "Design", Quantity: 10, UnitPrice: 100},
+			{Kind: "Service", Description: "Build", Quantity: 20, UnitPrice: 100},
+		},
+	})
+
+	inv, err := hv.FetchInvoices(ctx)
+	assert.NoError(err)
+	i := inv[0]
+	assert.Equal(3000.0, i.Amount)
+
+	subject := "Corrected invoice"
+	err = i.Update(ctx, &harvest.UpdateInvoice{Subject: &subject, DueDate: "2024-02-01"})
+	assert.NoError(err)
+	assert.Equal(subject, i.Subject)
+	assert.Equal("2024-02-01", i.DueDate)
+	assert.NotNil(i.Hv)
+
+	err = i.AddLineItems(ctx, &harvest.LineItem{Kind: "Product", Description: "Hosting", Quantity: 1, UnitPrice: 250})
+	assert.NoError(err)
+	assert.Len(i.LineItems, 3)
+	assert.Equal(3250.0, i.Amount)
+	assert.Equal(3250.0, i.DueAmount)
+
+	err = i.UpdateLineItems(ctx, &harvest.LineItem{ID: i.LineItems[0].ID, Quantity: 5})
+	assert.NoError(err)
+	assert.Equal(2750.0, i.Amount)
+	assert.Equal("Design", i.LineItems[0].Description)
+
+	err = i.RemoveLineItems(ctx, i.LineItems[1].ID)
+	assert.NoError(err)
+	assert.Len(i.LineItems, 2)
+	assert.Equal(750.0, i.Amount)
+
+	assert.Error(i.UpdateLineItems(ctx, &harvest.LineItem{Quantity: 1}))
+	assert.Error(i.AddLineItems(ctx, i.LineItems[0]))
+
+	assert.NoError(i.Delete(ctx))
+	assert.Nil(srv.Invoice(i.ID))
+}
+
+func TestImportInvoice(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	web := &harvest.Project{Name: "Website", Customer: acme}
+	srv.AddProject(web)
+	other := &harvest.Project{Name: "Other", Customer: acme}
+	srv.AddProject(other)
+
+	design := &harvest.Task{ID: 100, Name: "Design"}
+	build := &harvest.Task{ID: 101, Name: "Build"}
+	for _, e := range []*harvest.TimeEntry{
+		{Project: web, Task: design, SpentDate: "2024-01-05", Hours: 2, BillableRate: 100},
+		{Project: web, Task: design, SpentDate: "2024-01-06", Hours: 3, BillableRate: 100},
+		{Project: web, Task: build, SpentDate: "2024-01-07", Hours: 4, BillableRate: 80},
+		{Project: web, Task: build, SpentDate: "2024-02-01", Hours: 8, BillableRate: 80},
+		{Project: other, Task: build, SpentDate: "2024-01-07", Hours: 1, BillableRate: 80},
+	} {
+		srv.AddTimeEntry(e)
+	}
+	srv.AddExpense(&harvest.Expense{Project: web, SpentDate: "2024-01-10", TotalCost: 30})
+	srv.AddExpense(&harvest.Expense{Project: web, SpentDate: "2024-01-11", TotalCost: 20})
+
+	inv, err := hv.CreateInvoice(ctx, &harvest.Invoice{
+		ClientID: acme.ID,
+		Subject:  "January",
+		LineItemsImport: &harvest.LineItemsImport{
+			ProjectIDs: []int64{web.ID},
+			Time:       &harvest.TimeImport{SummaryType: "task", From: "2024-01-01", To: "2024-01-31"},
+			Expenses:   &harvest.ExpenseImport{SummaryType: "project", From: "2024-01-01", To: "2024-01-31"},
+		},
+	})
+	assert.NoError(err)
+	assert.Len(inv.LineItems, 3)
+	assert.Equal("Website: Design", inv.LineItems[0].Description)
+	assert.Equal(5.0, inv.LineItems[0].Quantity)
+	assert.Equal("Website: Build", inv.LineItems[1].Description)
+	assert.Equal(4.0, inv.LineItems[1].Quantity)
+	assert.Equal("Product", inv.LineItems[2].Kind)
+	assert.Equal(50.0, inv.LineItems[2].Amount)
+	assert.Equal(870.0, inv.Amount)
+
+	billed := 0
+	for e, err := range hv.TimeEntries(ctx) {
+		assert.NoError(err)
+		if e.IsBilled {
+			assert.Equal(inv.ID, e.Invoice.ID)
+			billed++
+		}
+	}
+	assert.Equal(3, billed)
+
+	// Nothing left to import
+	inv, err = hv.CreateInvoice(ctx, &harvest.Invoice{
+		ClientID: acme.ID,
+		LineItemsImport: &harvest.LineItemsImport{
+			ProjectIDs: []int64{web.ID},
+			Time:       &harvest.TimeImport{SummaryType: "project", From: "2024-01-01", To: "2024-01-31"},
+		},
+	})
+	assert.NoError(err)
+	assert.Len(inv.LineItems, 0)
+}
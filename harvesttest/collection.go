@@ -0,0 +1,161 @@
+package harvesttest
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// collection holds the records of one resource type.
+type collection struct {
+	// Path of the collection, nested collections are prefixed with the name
+	// of their parent (e.g. invoices/payments).
+	name string
+
+	// JSON field that holds the records in listings.
+	field string
+
+	// Field used by the from and to filters.
+	dateField string
+
+	items []*record
+
+	// Listings are sorted newest first, unless this is set.
+	oldestFirst bool
+
+	// Called when a record gets created, may return a validation message.
+	onCreate func(s *Server, rec *record) string
+
+	// Called before changes get merged into a record, can handle (and
+	// remove) fields that need more than a plain merge.
+	onUpdate func(s *Server, rec *record, changes map[string]any)
+
+	// Called before a record gets deleted, may return a validation message.
+	onDelete func(s *Server, rec *record) string
+
+	// Renders a record, if it needs more than the stored data.
+	render func(s *Server, rec *record) map[string]any
+
+	// Extra endpoints on a record, e.g. time_entries/{id}/stop.
+	actions map[string]func(s *Server, w http.ResponseWriter, r *http.Request, rec *record)
+
+	// Extra endpoints on the collection, e.g. invoices/{id}/messages/new.
+	// These get the parent record, if any.
+	listActions map[string]func(s *Server, w http.ResponseWriter, r *http.Request, parent *record)
+}
+
+// ref describes a nested object that is set with a foo_id field.
+type ref struct {
+	key        string
+	collection string
+	fields     []string
+}
+
+var refs = []ref{
+	{"client", "clients", []string{"name", "currency"}},
+	{"project", "projects", []string{"name", "code"}},
+	{"task", "tasks", []string{"name"}},
+	{"user", "users", []string{"name"}},
+	{"expense_category", "expense_categories", []string{"name", "unit_price", "unit_name"}},
+}
+
+// Listings of nested collections across all parents.
+var globalListings = map[string]string{
+	"task_assignments": "projects/task_assignments",
+	"user_assignments": "projects/user_assignments",
+}
+
+// find returns the record with the given ID. A parent of -1 matches any
+// parent.
+func (c *collection) find(id, parent int64) *record {
+	for _, rec := range c.items {
+		if rec.id == id && (parent < 0 || rec.parent == parent) {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (c *collection) remove(rec *record) {
+	c.items = slices.DeleteFunc(c.items, func(r *record) bool {
+		return r == rec
+	})
+}
+
+// matches applies the listing filters in q. Unknown parameters are ignored,
+// like Harvest does.
+func (c *collection) matches(rec *record, q url.Values) bool {
+	for k := range q {
+		v := q.Get(k)
+		switch {
+		case k == "page" || k == "per_page":
+		case k == "updated_since":
+			since, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				continue
+			}
+			updated, _ := time.Parse(time.RFC3339, str(rec.data["updated_at"]))
+			if updated.Before(since) {
+				return false
+			}
+		case k == "from" || k == "to":
+			if c.dateField == "" {
+				continue
+			}
+			date := str(rec.data[c.dateField])
+			if (k == "from" && date < v) || (k == "to" && date > v) {
+				return false
+			}
+		case k == "approval_status":
+			if str(rec.data[k]) != v {
+				return false
+			}
+		case k == "state":
+			if !slices.Contains(strings.Split(v, ","), str(rec.data["state"])) {
+				return false
+			}
+		case strings.HasPrefix(k, "is_") || k == "billable":
+			b, ok := rec.data[k].(bool)
+			if ok && b != (v == "true") {
+				return false
+			}
+		case strings.HasSuffix(k, "_id"):
+			id := toInt(v)
+			if nested, ok := rec.data[strings.TrimSuffix(k, "_id")].(map[string]any); ok {
+				if toInt(nested["id"]) != id {
+					return false
+				}
+			} else if field, ok := rec.data[k]; ok && toInt(field) != id {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// field returns a field of rec, as used in a nested object. Users have no
+// name, it is made from their first and last name.
+func field(rec *record, f string) any {
+	if v, ok := rec.data[f]; ok || f != "name" {
+		return v
+	}
+	if _, ok := rec.data["first_name"]; ok {
+		return strings.TrimSpace(str(rec.data["first_name"]) + " " + str(rec.data["last_name"]))
+	}
+	return nil
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func today() string {
+	return time.Now().Format("2006-01-02")
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,320 @@
+package harvesttest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serveReport computes a report from the stored time entries, expenses and
+// projects.
+func (s *Server) serveReport(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "", "")
+		return
+	}
+
+	var rows []map[string]any
+	if path == "project_budget" {
+		rows = s.projectBudget()
+	} else {
+		from, ok1 := reportDate(r.URL.Query().Get("from"))
+		to, ok2 := reportDate(r.URL.Query().Get("to"))
+		if !ok1 || !ok2 {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"message": "from and to are required dates"})
+			return
+		}
+
+		kind, group, _ := strings.Cut(path, "/")
+		switch {
+		case kind == "time" && slices.Contains([]string{"clients", "projects", "tasks", "team"}, group):
+			rows = s.timeReport(group, from, to)
+		case kind == "expenses" && slices.Contains([]string{"clients", "projects", "categories", "team"}, group):
+			rows = s.expenseReport(group, from, to)
+		case path == "uninvoiced":
+			rows = s.uninvoiced(from, to)
+		default:
+			writeError(w, http.StatusNotFound, "not_found", "The resource you requested could not be found")
+			return
+		}
+	}
+
+	// Page through the rows like any other listing.
+	c := &collection{name: "reports/" + path, field: "results", oldestFirst: true}
+	for i, row := range rows {
+		c.items = append(c.items, &record{id: int64(i + 1), data: row})
+	}
+	s.list(w, r, c, -1)
+}
+
+// reportDate parses a YYYYMMDD or YYYY-MM-DD date into the latter.
+func reportDate(v string) (string, bool) {
+	for _, layout := range []string{"20060102", "2006-01-02"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// reportRows groups rows by key, keeping them in order of appearance.
+type reportRows struct {
+	keys []string
+	rows map[string]map[string]any
+}
+
+func (rr *reportRows) get(key string, init func() map[string]any) map[string]any {
+	if rr.rows == nil {
+		rr.rows = make(map[string]map[string]any)
+	}
+	row, ok := rr.rows[key]
+	if !ok {
+		row = init()
+		rr.keys = append(rr.keys, key)
+		rr.rows[key] = row
+	}
+	return row
+}
+
+func (rr *reportRows) list() []map[string]any {
+	result := make([]map[string]any, 0, len(rr.keys))
+	for _, k := range rr.keys {
+		result = append(result, rr.rows[k])
+	}
+	return result
+}
+
+func (s *Server) timeReport(group, from, to string) []map[string]any {
+	var rr reportRows
+	for _, e := range s.collections["time_entries"].items {
+		date := str(e.data["spent_date"])
+		if date < from || date > to {
+			continue
+		}
+
+		client := s.clientOf(e)
+		k, init := s.reportGroup(group, e, client)
+		row := rr.get(k+"/"+str(client["currency"]), func() map[string]any {
+			row := init()
+			row["total_hours"] = 0.0
+			row["billable_hours"] = 0.0
+			row["billable_amount"] = 0.0
+			row["currency"] = client["currency"]
+			return row
+		})
+
+		hours := toFloat(e.data["hours"])
+		row["total_hours"] = round(toFloat(row["total_hours"]) + hours)
+		if b, _ := e.data["billable"].(bool); b {
+			row["billable_hours"] = round(toFloat(row["billable_hours"]) + hours)
+			row["billable_amount"] = round(toFloat(row["billable_amount"]) + hours*toFloat(e.data["billable_rate"]))
+		}
+	}
+	return rr.list()
+}
+
+func (s *Server) expenseReport(group, from, to string) []map[string]any {
+	var rr reportRows
+	for _, e := range s.collections["expenses"].items {
+		date := str(e.data["spent_date"])
+		if date < from || date > to {
+			continue
+		}
+
+		client := s.clientOf(e)
+		k, init := s.reportGroup(group, e, client)
+		row := rr.get(k+"/"+str(client["currency"]), func() map[string]any {
+			row := init()
+			row["total_amount"] = 0.0
+			row["billable_amount"] = 0.0
+			row["currency"] = client["currency"]
+			return row
+		})
+
+		cost := toFloat(e.data["total_cost"])
+		row["total_amount"] = round(toFloat(row["total_amount"]) + cost)
+		if b, _ := e.data["billable"].(bool); b {
+			row["billable_amount"] = round(toFloat(row["billable_amount"]) + cost)
+		}
+	}
+	return rr.list()
+}
+
+// reportGroup returns the key and initial fields of the row that a time
+// entry or expense gets grouped into.
+func (s *Server) reportGroup(group string, rec *record, client map[string]any) (string, func() map[string]any) {
+	obj := func(k string) map[string]any {
+		m, _ := rec.data[k].(map[string]any)
+		if m == nil {
+			m = map[string]any{}
+		}
+		return m
+	}
+
+	switch group {
+	case "clients":
+		return key(client), func() map[string]any {
+			return map[string]any{"client_id": client["id"], "client_name": client["name"]}
+		}
+	case "projects":
+		project := obj("project")
+		return key(project), func() map[string]any {
+			return map[string]any{
+				"client_id":    client["id"],
+				"client_name":  client["name"],
+				"project_id":   project["id"],
+				"project_name": project["name"],
+			}
+		}
+	case "tasks":
+		task := obj("task")
+		return key(task), func() map[string]any {
+			return map[string]any{"task_id": task["id"], "task_name": task["name"]}
+		}
+	case "categories":
+		category := obj("expense_category")
+		return key(category), func() map[string]any {
+			return map[string]any{"expense_category_id": category["id"], "expense_category_name": category["name"]}
+		}
+	default:
+		user := obj("user")
+		return key(user), func() map[string]any {
+			row := map[string]any{"user_id": user["id"], "user_name": user["name"]}
+			if u := s.collections["users"].find(toInt(user["id"]), -1); u != nil {
+				row["is_contractor"] = u.data["is_contractor"]
+				row["weekly_capacity"] = u.data["weekly_capacity"]
+				row["avatar_url"] = u.data["avatar_url"]
+			}
+			return row
+		}
+	}
+}
+
+func (s *Server) uninvoiced(from, to string) []map[string]any {
+	var rr reportRows
+	rowOf := func(rec *record) map[string]any {
+		client := s.clientOf(rec)
+		project, _ := rec.data["project"].(map[string]any)
+		return rr.get(key(project), func() map[string]any {
+			return map[string]any{
+				"client_id":           client["id"],
+				"client_name":         client["name"],
+				"project_id":          project["id"],
+				"project_name":        project["name"],
+				"currency":            client["currency"],
+				"total_hours":         0.0,
+				"uninvoiced_hours":    0.0,
+				"uninvoiced_expenses": 0.0,
+				"uninvoiced_amount":   0.0,
+			}
+		})
+	}
+	uninvoiced := func(rec *record) bool {
+		billable, _ := rec.data["billable"].(bool)
+		billed, _ := rec.data["is_billed"].(bool)
+		date := str(rec.data["spent_date"])
+		return billable && !billed && date >= from && date <= to
+	}
+
+	for _, e := range s.collections["time_entries"].items {
+		date := str(e.data["spent_date"])
+		if date < from || date > to {
+			continue
+		}
+		row := rowOf(e)
+		hours := toFloat(e.data["hours"])
+		row["total_hours"] = round(toFloat(row["total_hours"]) + hours)
+		if uninvoiced(e) {
+			row["uninvoiced_hours"] = round(toFloat(row["uninvoiced_hours"]) + hours)
+			row["uninvoiced_amount"] = round(toFloat(row["uninvoiced_amount"]) + hours*toFloat(e.data["billable_rate"]))
+		}
+	}
+	for _, e := range s.collections["expenses"].items {
+		if !uninvoiced(e) {
+			continue
+		}
+		row := rowOf(e)
+		cost := toFloat(e.data["total_cost"])
+		row["uninvoiced_expenses"] = round(toFloat(row["uninvoiced_expenses"]) + cost)
+		row["uninvoiced_amount"] = round(toFloat(row["uninvoiced_amount"]) + cost)
+	}
+	return rr.list()
+}
+
+func (s *Server) projectBudget() []map[string]any {
+	result := make([]map[string]any, 0)
+	for _, p := range s.collections["projects"].items {
+		client, _ := p.data["client"].(map[string]any)
+		budgetBy := str(p.data["budget_by"])
+		byCost := budgetBy == "project_cost" || budgetBy == "task_fees"
+
+		budget := toFloat(p.data["budget"])
+		if byCost {
+			budget = toFloat(p.data["cost_budget"])
+		}
+
+		spent := 0.0
+		for _, e := range s.collections["time_entries"].items {
+			if project, _ := e.data["project"].(map[string]any); toInt(project["id"]) != p.id {
+				continue
+			}
+			if byCost {
+				spent += toFloat(e.data["hours"]) * toFloat(e.data["billable_rate"])
+			} else {
+				spent += toFloat(e.data["hours"])
+			}
+		}
+		if withExpenses, _ := p.data["cost_budget_include_expenses"].(bool); byCost && withExpenses {
+			for _, e := range s.collections["expenses"].items {
+				if project, _ := e.data["project"].(map[string]any); toInt(project["id"]) == p.id {
+					spent += toFloat(e.data["total_cost"])
+				}
+			}
+		}
+
+		result = append(result, map[string]any{
+			"client_id":         client["id"],
+			"client_name":       client["name"],
+			"project_id":        p.id,
+			"project_name":      p.data["name"],
+			"budget_by":         budgetBy,
+			"budget_is_monthly": p.data["budget_is_monthly"] == true,
+			"is_active":         p.data["is_active"] == true,
+			"budget":            budget,
+			"budget_spent":      round(spent),
+			"budget_remaining":  round(budget - spent),
+		})
+	}
+	return result
+}
+
+// clientOf returns the client of a time entry or expense, falling back to
+// the client of its project.
+func (s *Server) clientOf(rec *record) map[string]any {
+	client, _ := rec.data["client"].(map[string]any)
+	if client == nil {
+		project, _ := rec.data["project"].(map[string]any)
+		if p := s.collections["projects"].find(toInt(project["id"]), -1); p != nil {
+			client, _ = p.data["client"].(map[string]any)
+		}
+	}
+	result := map[string]any{}
+	for k, v := range client {
+		result[k] = v
+	}
+
+	// The currency isn't always part of the nested client.
+	if result["currency"] == nil {
+		if c := s.collections["clients"].find(toInt(result["id"]), -1); c != nil {
+			result["currency"] = c.data["currency"]
+		}
+	}
+	return result
+}
+
+// key identifies the row of a nested object.
+func key(obj map[string]any) string {
+	return strconv.FormatInt(toInt(obj["id"]), 10)
+}
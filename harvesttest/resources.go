@@ -0,0 +1,1314 @@
+package harvesttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rubenv/harvest"
+)
+
+func collections() []*collection {
+	return []*collection{
+		{
+			name:     "clients",
+			field:    "clients",
+			onCreate: createCustomer,
+			onDelete: deleteCustomer,
+		},
+		{
+			name:     "projects",
+			field:    "projects",
+			onCreate: createProject,
+		},
+		{
+			name:     "contacts",
+			field:    "contacts",
+			onCreate: createContact,
+		},
+		{
+			name:      "invoices",
+			field:     "invoices",
+			dateField: "issue_date",
+			onCreate:  createInvoice,
+			onUpdate:  updateInvoice,
+		},
+		{
+			name:      "estimates",
+			field:     "estimates",
+			dateField: "issue_date",
+			onCreate:  createEstimate,
+			onUpdate:  updateLineItems,
+		},
+		{
+			name:     "estimates/messages",
+			field:    "estimate_messages",
+			onCreate: createEstimateMessage,
+		},
+		{
+			name:     "invoices/messages",
+			field:    "invoice_messages",
+			onCreate: createInvoiceMessage,
+			listActions: map[string]func(*Server, http.ResponseWriter, *http.Request, *record){
+				"new": previewInvoiceMessage,
+			},
+		},
+		{
+			name:     "invoices/payments",
+			field:    "invoice_payments",
+			onCreate: createPayment,
+			onDelete: deletePayment,
+		},
+		{
+			name:      "expenses",
+			field:     "expenses",
+			dateField: "spent_date",
+			onCreate:  createExpense,
+			onUpdate:  updateExpense,
+			onDelete:  deleteExpense,
+		},
+		{
+			name:     "expense_categories",
+			field:    "expense_categories",
+			onCreate: createExpenseCategory,
+			onDelete: deleteExpenseCategory,
+		},
+		{
+			name:     "tasks",
+			field:    "tasks",
+			onCreate: createTask,
+			onDelete: deleteTask,
+		},
+		{
+			name:     "projects/task_assignments",
+			field:    "task_assignments",
+			onCreate: createTaskAssignment,
+			onDelete: deleteTaskAssignment,
+		},
+		{
+			name:     "users",
+			field:    "users",
+			onCreate: createUser,
+			onDelete: deleteUser,
+			actions: map[string]func(*Server, http.ResponseWriter, *http.Request, *record){
+				"project_assignments": listProjectAssignments,
+			},
+		},
+		{
+			name:        "users/billable_rates",
+			field:       "billable_rates",
+			oldestFirst: true,
+			onCreate:    createRate("default_hourly_rate"),
+		},
+		{
+			name:        "users/cost_rates",
+			field:       "cost_rates",
+			oldestFirst: true,
+			onCreate:    createRate("cost_rate"),
+		},
+		{
+			name:     "roles",
+			field:    "roles",
+			onCreate: createRole,
+		},
+		{
+			name:     "projects/user_assignments",
+			field:    "user_assignments",
+			onCreate: createUserAssignment,
+			onDelete: deleteUserAssignment,
+		},
+		{
+			name:     "invoice_item_categories",
+			field:    "invoice_item_categories",
+			onCreate: createInvoiceItemCategory,
+			onDelete: deleteInvoiceItemCategory,
+		},
+		{
+			name:      "time_entries",
+			field:     "time_entries",
+			dateField: "spent_date",
+			onCreate:  createTimeEntry,
+			actions: map[string]func(*Server, http.ResponseWriter, *http.Request, *record){
+				"restart": timer(true),
+				"stop":    timer(false),
+			},
+		},
+	}
+}
+
+func setDefaults(defaults map[string]any) func(s *Server, rec *record) string {
+	return func(s *Server, rec *record) string {
+		for k, v := range defaults {
+			if isEmpty(rec.data[k]) {
+				rec.data[k] = v
+			}
+		}
+		return ""
+	}
+}
+
+func createCustomer(s *Server, rec *record) string {
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	for _, c := range s.collections["clients"].items {
+		if c != rec && c.data["name"] == rec.data["name"] {
+			return "Name has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"is_active":     true,
+		"currency":      "EUR",
+		"address":       nil,
+		"statement_key": randomKey(),
+	})(s, rec)
+	return ""
+}
+
+func deleteCustomer(s *Server, rec *record) string {
+	for _, name := range []string{"projects", "invoices"} {
+		for _, r := range s.collections[name].items {
+			if c, ok := r.data["client"].(map[string]any); ok && toInt(c["id"]) == rec.id {
+				return fmt.Sprintf("Client has %s, archive it instead", name)
+			}
+		}
+	}
+	return ""
+}
+
+func createContact(s *Server, rec *record) string {
+	if _, ok := rec.data["client"]; !ok {
+		return "Client can't be blank"
+	}
+	if str(rec.data["first_name"]) == "" {
+		return "First name can't be blank"
+	}
+	return ""
+}
+
+func createProject(s *Server, rec *record) string {
+	if _, ok := rec.data["client"]; !ok {
+		return "Client can't be blank"
+	}
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	for _, p := range s.collections["projects"].items {
+		if p != rec && p.data["name"] == rec.data["name"] {
+			return "Name has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"is_active":   true,
+		"bill_by":     "none",
+		"budget_by":   "none",
+		"is_billable": false,
+	})(s, rec)
+	return ""
+}
+
+func createInvoice(s *Server, rec *record) string {
+	if _, ok := rec.data["client"]; !ok {
+		return "Client can't be blank"
+	}
+
+	setDefaults(map[string]any{
+		"state":      "draft",
+		"number":     strconv.FormatInt(rec.id, 10),
+		"client_key": randomKey(),
+		"issue_date": today(),
+		"currency":   "EUR",
+	})(s, rec)
+
+	if msg := checkKinds(s, rec); msg != "" {
+		return msg
+	}
+
+	if imp, ok := rec.data["line_items_import"].(map[string]any); ok {
+		delete(rec.data, "line_items_import")
+		importLineItems(s, rec, imp)
+	}
+
+	computeAmounts(s, rec)
+	if _, ok := rec.data["due_amount"]; !ok && rec.data["state"] != "paid" {
+		rec.data["due_amount"] = rec.data["amount"]
+	}
+	return ""
+}
+
+func updateInvoice(s *Server, rec *record, changes map[string]any) {
+	if _, ok := changes["line_items"]; !ok {
+		return
+	}
+	updateLineItems(s, rec, changes)
+
+	paid := 0.0
+	for _, p := range s.collections["invoices/payments"].items {
+		if p.parent == rec.id {
+			paid += toFloat(p.data["amount"])
+		}
+	}
+	rec.data["due_amount"] = round(toFloat(rec.data["amount"]) - paid)
+}
+
+// importLineItems turns uninvoiced time entries and expenses into line items
+// and marks them as billed.
+func importLineItems(s *Server, rec *record, imp map[string]any) {
+	projects := make(map[int64]bool)
+	for _, id := range toSlice(imp["project_ids"]) {
+		projects[toInt(id)] = true
+	}
+
+	items := toSlice(rec.data["line_items"])
+	for _, src := range []struct {
+		collection string
+		field      string
+		kind       string
+	}{
+		{"time_entries", "time", "Service"},
+		{"expenses", "expenses", "Product"},
+	} {
+		opts, ok := imp[src.field].(map[string]any)
+		if !ok {
+			continue
+		}
+		summary := str(opts["summary_type"])
+
+		groups := make(map[string]map[string]any)
+		var order []string
+		for _, e := range s.collections[src.collection].items {
+			project, _ := e.data["project"].(map[string]any)
+			date := str(e.data["spent_date"])
+			if project == nil || !projects[toInt(project["id"])] || e.data["is_billed"] == true ||
+				(str(opts["from"]) != "" && date < str(opts["from"])) ||
+				(str(opts["to"]) != "" && date > str(opts["to"])) {
+				continue
+			}
+
+			desc := str(project["name"])
+			switch summary {
+			case "task":
+				desc += ": " + name(e.data["task"])
+			case "category":
+				desc += ": " + name(e.data["expense_category"])
+			case "people":
+				desc += ": " + name(e.data["user"])
+			case "detailed":
+				desc += fmt.Sprintf(": %s (%s)", str(e.data["notes"]), date)
+			}
+
+			key := desc
+			if summary == "detailed" {
+				key = strconv.FormatInt(e.id, 10)
+			}
+			li, ok := groups[key]
+			if !ok {
+				li = map[string]any{
+					"kind":        src.kind,
+					"description": desc,
+					"project":     project,
+					"quantity":    0.0,
+					"unit_price":  0.0,
+				}
+				groups[key] = li
+				order = append(order, key)
+			}
+			if src.field == "time" {
+				li["quantity"] = toFloat(li["quantity"]) + toFloat(e.data["hours"])
+				li["unit_price"] = toFloat(e.data["billable_rate"])
+			} else {
+				li["quantity"] = 1.0
+				li["unit_price"] = toFloat(li["unit_price"]) + toFloat(e.data["total_cost"])
+			}
+
+			e.data["is_billed"] = true
+			e.data["invoice"] = map[string]any{"id": rec.id, "number": rec.data["number"]}
+			e.touch()
+		}
+
+		for _, key := range order {
+			items = append(items, groups[key])
+		}
+	}
+	rec.data["line_items"] = items
+}
+
+func name(v any) string {
+	m, _ := v.(map[string]any)
+	return str(m["name"])
+}
+
+func createEstimate(s *Server, rec *record) string {
+	if _, ok := rec.data["client"]; !ok {
+		return "Client can't be blank"
+	}
+
+	setDefaults(map[string]any{
+		"state":      "draft",
+		"number":     strconv.FormatInt(rec.id, 10),
+		"client_key": randomKey(),
+		"issue_date": today(),
+		"currency":   "EUR",
+		"creator":    map[string]any{"id": 1, "name": "Test User"},
+	})(s, rec)
+
+	computeAmounts(s, rec)
+	return ""
+}
+
+// computeAmounts fills in line item IDs and amounts and the document total.
+func computeAmounts(s *Server, rec *record) {
+	amount := 0.0
+	items := toSlice(rec.data["line_items"])
+	for _, item := range items {
+		li, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if toInt(li["id"]) == 0 {
+			s.nextID++
+			li["id"] = s.nextID
+		}
+		li["amount"] = round(toFloat(li["quantity"]) * toFloat(li["unit_price"]))
+		amount += toFloat(li["amount"])
+	}
+	if len(items) > 0 {
+		rec.data["amount"] = round(amount)
+	}
+}
+
+// updateLineItems merges line items like Harvest does: items with an ID are
+// updated (or removed when _destroy is set), others are added.
+func updateLineItems(s *Server, rec *record, changes map[string]any) {
+	updates, ok := changes["line_items"].([]any)
+	if !ok {
+		return
+	}
+	delete(changes, "line_items")
+
+	items := toSlice(rec.data["line_items"])
+	for _, u := range updates {
+		update, ok := u.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		id := toInt(update["id"])
+		if id == 0 {
+			items = append(items, update)
+			continue
+		}
+		for i, item := range items {
+			li := item.(map[string]any)
+			if toInt(li["id"]) != id {
+				continue
+			}
+			if update["_destroy"] == true {
+				items = append(items[:i], items[i+1:]...)
+			} else {
+				for k, v := range update {
+					li[k] = v
+				}
+			}
+			break
+		}
+	}
+
+	rec.data["line_items"] = items
+	rec.data["amount"] = 0.0
+	computeAmounts(s, rec)
+}
+
+func createEstimateMessage(s *Server, rec *record) string {
+	estimate := s.collections["estimates"].find(rec.parent, -1)
+	state := str(estimate.data["state"])
+	event := str(rec.data["event_type"])
+
+	switch {
+	case event == "" && len(toSlice(rec.data["recipients"])) == 0:
+		return "Recipients can't be blank"
+	case event == "" || event == "send":
+		if state == "draft" {
+			estimate.data["state"] = "sent"
+		}
+		estimate.data["sent_at"] = now()
+	case event == "accept" && state == "sent":
+		estimate.data["state"] = "accepted"
+		estimate.data["accepted_at"] = now()
+	case event == "decline" && state == "sent":
+		estimate.data["state"] = "declined"
+		estimate.data["declined_at"] = now()
+	case event == "re-open" && (state == "accepted" || state == "declined"):
+		estimate.data["state"] = "sent"
+		estimate.data["accepted_at"] = nil
+		estimate.data["declined_at"] = nil
+	default:
+		return fmt.Sprintf("Can't %s an estimate that is %s", event, state)
+	}
+	estimate.touch()
+
+	setDefaults(map[string]any{
+		"sent_by":         "Test User",
+		"sent_by_email":   "test@example.com",
+		"sent_from":       "Test User",
+		"sent_from_email": "test@example.com",
+		"recipients":      []any{},
+	})(s, rec)
+	return ""
+}
+
+func createInvoiceMessage(s *Server, rec *record) string {
+	invoice := s.collections["invoices"].find(rec.parent, -1)
+	state := str(invoice.data["state"])
+	event := str(rec.data["event_type"])
+
+	switch {
+	case event == "" && len(toSlice(rec.data["recipients"])) == 0:
+		return "Recipients can't be blank"
+	case event == "" || event == "send":
+		invoice.data["sent_at"] = now()
+		if state == "draft" {
+			invoice.data["state"] = "open"
+		}
+	case event == "close" && state == "open":
+		invoice.data["state"] = "closed"
+		invoice.data["closed_at"] = now()
+	case event == "re-open" && state == "closed":
+		invoice.data["state"] = "open"
+		invoice.data["closed_at"] = nil
+	case event == "draft" && state == "open":
+		invoice.data["state"] = "draft"
+		invoice.data["sent_at"] = nil
+	default:
+		return fmt.Sprintf("Can't %s an invoice that is %s", event, state)
+	}
+	invoice.touch()
+
+	setDefaults(map[string]any{
+		"sent_by":       "Test User",
+		"sent_by_email": "test@example.com",
+		"sent_from":     "Test User",
+	})(s, rec)
+	return ""
+}
+
+func previewInvoiceMessage(s *Server, w http.ResponseWriter, r *http.Request, invoice *record) {
+	if r.Method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "", "")
+		return
+	}
+
+	q := r.URL.Query()
+	number := str(invoice.data["number"])
+	subject := fmt.Sprintf("Invoice #%s from Test Company", number)
+	body := fmt.Sprintf("Please find invoice #%s attached.", number)
+	switch {
+	case q.Get("thank_you") == "true":
+		subject = fmt.Sprintf("Thank you for paying invoice #%s", number)
+		body = "We have received your payment, thank you!"
+	case q.Get("reminder") == "true":
+		subject = fmt.Sprintf("Invoice #%s is past due", number)
+		body = fmt.Sprintf("Invoice #%s is now past due, please pay at your earliest convenience.", number)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"invoice_id": invoice.id,
+		"subject":    subject,
+		"body":       body,
+		"reminder":   q.Get("reminder") == "true",
+		"thank_you":  q.Get("thank_you") == "true",
+	})
+}
+
+func createPayment(s *Server, rec *record) string {
+	amount := toFloat(rec.data["amount"])
+	if amount <= 0 {
+		return "Amount must be greater than 0"
+	}
+
+	setDefaults(map[string]any{
+		"paid_date":         today(),
+		"paid_at":           now(),
+		"recorded_by":       "Test User",
+		"recorded_by_email": "test@example.com",
+	})(s, rec)
+
+	invoice := s.collections["invoices"].find(rec.parent, -1)
+	due := round(toFloat(invoice.data["due_amount"]) - amount)
+	invoice.data["due_amount"] = due
+	if due <= 0 {
+		invoice.data["state"] = "paid"
+		invoice.data["paid_at"] = rec.data["paid_at"]
+		invoice.data["paid_date"] = rec.data["paid_date"]
+	}
+	invoice.touch()
+	return ""
+}
+
+func deletePayment(s *Server, rec *record) string {
+	invoice := s.collections["invoices"].find(rec.parent, -1)
+	invoice.data["due_amount"] = round(toFloat(invoice.data["due_amount"]) + toFloat(rec.data["amount"]))
+	if invoice.data["state"] == "paid" {
+		invoice.data["state"] = "open"
+		invoice.data["paid_at"] = nil
+		invoice.data["paid_date"] = nil
+	}
+	invoice.touch()
+	return ""
+}
+
+func createExpense(s *Server, rec *record) string {
+	if _, ok := rec.data["project"]; !ok {
+		return "Project can't be blank"
+	}
+
+	normalizeExpense(rec.data)
+	s.storeReceipt(rec, rec.data)
+	setDefaults(map[string]any{
+		"spent_date":      today(),
+		"billable":        true,
+		"is_locked":       false,
+		"is_billed":       false,
+		"is_closed":       false,
+		"approval_status": "unsubmitted",
+	})(s, rec)
+	return ""
+}
+
+func updateExpense(s *Server, rec *record, changes map[string]any) {
+	normalizeExpense(changes)
+	s.storeReceipt(rec, changes)
+	if del, _ := changes["delete_receipt"].(bool); del {
+		delete(s.receipts, rec.id)
+		changes["receipt"] = nil
+	}
+	delete(changes, "delete_receipt")
+}
+
+func deleteExpense(s *Server, rec *record) string {
+	if locked, _ := rec.data["is_locked"].(bool); locked {
+		return "Expense is locked and can't be deleted"
+	}
+	delete(s.receipts, rec.id)
+	return ""
+}
+
+// normalizeExpense turns the numbers in multipart requests back into numbers
+// and computes the total cost for unit based categories.
+func normalizeExpense(data map[string]any) {
+	for _, k := range []string{"total_cost", "units"} {
+		if v, ok := data[k]; ok {
+			data[k] = toFloat(v)
+		}
+	}
+
+	category, _ := data["expense_category"].(map[string]any)
+	if units, ok := data["units"]; ok && category != nil && toFloat(category["unit_price"]) > 0 {
+		data["total_cost"] = round(toFloat(units) * toFloat(category["unit_price"]))
+	}
+}
+
+// storeReceipt keeps an uploaded receipt, so it can be downloaded.
+func (s *Server) storeReceipt(rec *record, data map[string]any) {
+	receipt, ok := data["receipt"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	content, ok := receipt["content"].([]byte)
+	if ok {
+		delete(receipt, "content")
+		s.receipts[rec.id] = &attachment{
+			filename: str(receipt["file_name"]),
+			data:     content,
+		}
+	}
+	receipt["url"] = fmt.Sprintf("%s/expenses/%d/receipt", s.URL, rec.id)
+}
+
+func createExpenseCategory(s *Server, rec *record) string {
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	for _, c := range s.collections["expense_categories"].items {
+		if c != rec && c.data["name"] == rec.data["name"] {
+			return "Name has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"is_active":  true,
+		"unit_name":  nil,
+		"unit_price": nil,
+	})(s, rec)
+	return ""
+}
+
+func deleteExpenseCategory(s *Server, rec *record) string {
+	for _, r := range s.collections["expenses"].items {
+		if c, ok := r.data["expense_category"].(map[string]any); ok && toInt(c["id"]) == rec.id {
+			return "Expense category is in use, archive it instead"
+		}
+	}
+	return ""
+}
+
+func createTask(s *Server, rec *record) string {
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	for _, t := range s.collections["tasks"].items {
+		if t != rec && t.data["name"] == rec.data["name"] {
+			return "Name has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"billable_by_default": true,
+		"default_hourly_rate": nil,
+		"is_default":          false,
+		"is_active":           true,
+	})(s, rec)
+	return ""
+}
+
+func deleteTask(s *Server, rec *record) string {
+	if s.hasTime(0, rec.id) {
+		return "Task has tracked time, archive it instead"
+	}
+	return ""
+}
+
+func createTaskAssignment(s *Server, rec *record) string {
+	task, ok := rec.data["task"].(map[string]any)
+	if !ok {
+		return "Task can't be blank"
+	}
+	for _, a := range s.collections["projects/task_assignments"].items {
+		if a != rec && a.parent == rec.parent && toInt(a.data["task"].(map[string]any)["id"]) == toInt(task["id"]) {
+			return "Task has already been assigned to this project"
+		}
+	}
+
+	if project := s.collections["projects"].find(rec.parent, -1); project != nil {
+		rec.data["project"] = map[string]any{
+			"id":   project.id,
+			"name": project.data["name"],
+			"code": project.data["code"],
+		}
+	}
+
+	rate := any(nil)
+	if t := s.collections["tasks"].find(toInt(task["id"]), -1); t != nil {
+		rate = t.data["default_hourly_rate"]
+	}
+	setDefaults(map[string]any{
+		"is_active":   true,
+		"billable":    true,
+		"hourly_rate": rate,
+		"budget":      nil,
+	})(s, rec)
+	return ""
+}
+
+func deleteTaskAssignment(s *Server, rec *record) string {
+	if s.hasTime(rec.parent, toInt(rec.data["task"].(map[string]any)["id"])) {
+		return "Task has tracked time on this project, archive it instead"
+	}
+	return ""
+}
+
+// hasTime reports whether time has been tracked on the given task, in the
+// given project (or any, if zero).
+func (s *Server) hasTime(projectID, taskID int64) bool {
+	for _, e := range s.collections["time_entries"].items {
+		task, _ := e.data["task"].(map[string]any)
+		project, _ := e.data["project"].(map[string]any)
+		if task != nil && toInt(task["id"]) == taskID && (projectID == 0 || (project != nil && toInt(project["id"]) == projectID)) {
+			return true
+		}
+	}
+	return false
+}
+
+func createUser(s *Server, rec *record) string {
+	for _, k := range []string{"first_name", "last_name", "email"} {
+		if str(rec.data[k]) == "" {
+			return fmt.Sprintf("%s can't be blank", strings.ToUpper(k[:1])+strings.ReplaceAll(k[1:], "_", " "))
+		}
+	}
+	for _, u := range s.collections["users"].items {
+		if u != rec && u.data["email"] == rec.data["email"] {
+			return "Email has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"telephone":                         "",
+		"timezone":                          "Eastern Time (US & Canada)",
+		"has_access_to_all_future_projects": false,
+		"is_contractor":                     false,
+		"is_active":                         true,
+		"weekly_capacity":                   126000,
+		"default_hourly_rate":               nil,
+		"cost_rate":                         nil,
+		"roles":                             []any{},
+		"access_roles":                      []any{"member"},
+	})(s, rec)
+	return ""
+}
+
+func deleteUser(s *Server, rec *record) string {
+	for _, name := range []string{"time_entries", "expenses"} {
+		for _, r := range s.collections[name].items {
+			if u, ok := r.data["user"].(map[string]any); ok && toInt(u["id"]) == rec.id {
+				return fmt.Sprintf("User has %s, archive it instead", strings.ReplaceAll(name, "_", " "))
+			}
+		}
+	}
+	return ""
+}
+
+// me returns the ID of the user that owns the token.
+func (s *Server) me() int64 {
+	if s.UserID != 0 {
+		return s.UserID
+	}
+	users := s.collections["users"].items
+	if len(users) == 0 {
+		return 0
+	}
+	return users[0].id
+}
+
+// createRate adds a billable or cost rate, ending the previous one and
+// updating the user when it is the current rate.
+func createRate(userField string) func(s *Server, rec *record) string {
+	return func(s *Server, rec *record) string {
+		if _, ok := rec.data["amount"]; !ok {
+			return "Amount can't be blank"
+		}
+		setDefaults(map[string]any{
+			"start_date": nil,
+			"end_date":   nil,
+		})(s, rec)
+
+		start, err := time.Parse("2006-01-02", str(rec.data["start_date"]))
+		if err != nil && rec.data["start_date"] != nil {
+			return "Start date is invalid"
+		}
+		if err == nil && start.After(time.Now()) {
+			return "Start date can't be in the future"
+		}
+
+		name := "users/billable_rates"
+		if userField == "cost_rate" {
+			name = "users/cost_rates"
+		}
+		for _, r := range s.collections[name].items {
+			if r != rec && r.parent == rec.parent && r.data["end_date"] == nil && err == nil {
+				r.data["end_date"] = start.AddDate(0, 0, -1).Format("2006-01-02")
+				r.touch()
+			}
+		}
+
+		if user := s.collections["users"].find(rec.parent, -1); user != nil {
+			user.data[userField] = rec.data["amount"]
+			user.touch()
+		}
+		return ""
+	}
+}
+
+func createRole(s *Server, rec *record) string {
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	setDefaults(map[string]any{
+		"user_ids": []any{},
+	})(s, rec)
+	return ""
+}
+
+func createUserAssignment(s *Server, rec *record) string {
+	user, ok := rec.data["user"].(map[string]any)
+	if !ok {
+		return "User can't be blank"
+	}
+	for _, a := range s.collections["projects/user_assignments"].items {
+		if a != rec && a.parent == rec.parent && toInt(a.data["user"].(map[string]any)["id"]) == toInt(user["id"]) {
+			return "User has already been assigned to this project"
+		}
+	}
+
+	if project := s.collections["projects"].find(rec.parent, -1); project != nil {
+		rec.data["project"] = map[string]any{
+			"id":   project.id,
+			"name": project.data["name"],
+			"code": project.data["code"],
+		}
+	}
+
+	rate := any(nil)
+	if u := s.collections["users"].find(toInt(user["id"]), -1); u != nil {
+		rate = u.data["default_hourly_rate"]
+	}
+	setDefaults(map[string]any{
+		"is_active":          true,
+		"is_project_manager": false,
+		"use_default_rates":  true,
+		"hourly_rate":        rate,
+		"budget":             nil,
+	})(s, rec)
+	return ""
+}
+
+func deleteUserAssignment(s *Server, rec *record) string {
+	userID := toInt(rec.data["user"].(map[string]any)["id"])
+	for _, e := range s.collections["time_entries"].items {
+		user, _ := e.data["user"].(map[string]any)
+		project, _ := e.data["project"].(map[string]any)
+		if user != nil && project != nil && toInt(user["id"]) == userID && toInt(project["id"]) == rec.parent {
+			return "User has tracked time on this project, archive it instead"
+		}
+	}
+	return ""
+}
+
+// listProjectAssignments lists the user assignments of a user, along with
+// the client and task assignments of each project.
+func listProjectAssignments(s *Server, w http.ResponseWriter, r *http.Request, user *record) {
+	q := r.URL.Query()
+	q.Set("user_id", strconv.FormatInt(user.id, 10))
+	r.URL.RawQuery = q.Encode()
+
+	view := *s.collections["projects/user_assignments"]
+	view.field = "project_assignments"
+	view.render = func(s *Server, rec *record) map[string]any {
+		result := make(map[string]any)
+		for k, v := range rec.data {
+			if k != "user" {
+				result[k] = v
+			}
+		}
+		if project := s.collections["projects"].find(rec.parent, -1); project != nil {
+			result["client"] = project.data["client"]
+		}
+
+		tasks := make([]any, 0)
+		for _, a := range s.collections["projects/task_assignments"].items {
+			if a.parent == rec.parent {
+				tasks = append(tasks, a.data)
+			}
+		}
+		result["task_assignments"] = tasks
+		return result
+	}
+	s.list(w, r, &view, -1)
+}
+
+func createInvoiceItemCategory(s *Server, rec *record) string {
+	if str(rec.data["name"]) == "" {
+		return "Name can't be blank"
+	}
+	for _, c := range s.collections["invoice_item_categories"].items {
+		if c != rec && c.data["name"] == rec.data["name"] {
+			return "Name has already been taken"
+		}
+	}
+
+	setDefaults(map[string]any{
+		"use_as_service": false,
+		"use_as_expense": false,
+	})(s, rec)
+	return ""
+}
+
+func deleteInvoiceItemCategory(s *Server, rec *record) string {
+	if rec.data["use_as_service"] == true || rec.data["use_as_expense"] == true {
+		return "Invoice item category is in use and can't be deleted"
+	}
+	return ""
+}
+
+// checkKinds validates the kind of the line items of an invoice. This is
+// only done once invoice item categories have been added, so fixtures don't
+// need them.
+func checkKinds(s *Server, rec *record) string {
+	categories := s.collections["invoice_item_categories"].items
+	if len(categories) == 0 {
+		return ""
+	}
+
+	for _, li := range toSlice(rec.data["line_items"]) {
+		kind := str(li.(map[string]any)["kind"])
+		if kind == "" {
+			continue
+		}
+		if !slices.ContainsFunc(categories, func(c *record) bool { return c.data["name"] == kind }) {
+			return fmt.Sprintf("Kind %q is not an invoice item category", kind)
+		}
+	}
+	return ""
+}
+
+func createTimeEntry(s *Server, rec *record) string {
+	_, hasHours := rec.data["hours"]
+	_, hasStart := rec.data["started_time"]
+	running := !hasHours && !hasStart
+
+	setDefaults(map[string]any{
+		"spent_date":          today(),
+		"hours":               0.0,
+		"is_running":          running,
+		"is_locked":           false,
+		"is_billed":           false,
+		"is_closed":           false,
+		"billable":            true,
+		"budgeted":            true,
+		"notes":               nil,
+		"ended_time":          nil,
+		"invoice":             nil,
+		"cost_rate":           nil,
+		"hours_without_timer": rec.data["hours"],
+		"approval_status":     "unsubmitted",
+	})(s, rec)
+	if running {
+		rec.data["timer_started_at"] = now()
+	}
+	rec.data["rounded_hours"] = rec.data["hours"]
+	return ""
+}
+
+func timer(start bool) func(s *Server, w http.ResponseWriter, r *http.Request, rec *record) {
+	return func(s *Server, w http.ResponseWriter, r *http.Request, rec *record) {
+		if r.Method != "PATCH" {
+			writeError(w, http.StatusMethodNotAllowed, "", "")
+			return
+		}
+		if rec.data["is_running"] == start {
+			msg := "Time entry is already stopped"
+			if start {
+				msg = "Time entry is already running"
+			}
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"message": msg})
+			return
+		}
+
+		rec.data["is_running"] = start
+		if start {
+			rec.data["timer_started_at"] = now()
+		} else {
+			rec.data["timer_started_at"] = nil
+		}
+		rec.touch()
+		writeJSON(w, http.StatusOK, rec.data)
+	}
+}
+
+// serveClientPage serves the client-facing invoice pages, which are used to
+// download invoices and their attachments, and expense receipts.
+func (s *Server) serveClientPage(w http.ResponseWriter, r *http.Request) {
+	if id, ok := strings.CutPrefix(r.URL.Path, "/expenses/"); ok && r.Method == "GET" {
+		id, ok = strings.CutSuffix(id, "/receipt")
+		receipt := s.receipts[toInt(id)]
+		if !ok || receipt == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(receipt.data)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/client/invoices/")
+	if !ok || r.Method != "GET" {
+		http.NotFound(w, r)
+		return
+	}
+
+	key, rest, _ := strings.Cut(path, "/")
+	key, pdf := strings.CutSuffix(key, ".pdf")
+
+	var invoice *record
+	for _, rec := range s.collections["invoices"].items {
+		if rec.data["client_key"] == key {
+			invoice = rec
+		}
+	}
+	if invoice == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case pdf && rest == "":
+		doc, ok := s.documents[invoice.id]
+		if !ok {
+			doc = []byte(fmt.Sprintf("%%PDF-1.4\n%% Invoice %s\n", str(invoice.data["number"])))
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(doc)
+	case rest == "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, "<html><body><h1>Invoice %s</h1><ul id=\"document-attachments\">\n", html.EscapeString(str(invoice.data["number"])))
+		for i, a := range s.attachments[invoice.id] {
+			fmt.Fprintf(w, "<li><a href=\"/client/invoices/%s/attachments/%d\">%s</a></li>\n", key, i, html.EscapeString(a.filename))
+		}
+		fmt.Fprint(w, "</ul></body></html>\n")
+	default:
+		n, err := strconv.Atoi(strings.TrimPrefix(rest, "attachments/"))
+		if err != nil || n < 0 || n >= len(s.attachments[invoice.id]) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(s.attachments[invoice.id][n].data)
+	}
+}
+
+// AddCustomer adds a client, setting its ID if empty.
+func (s *Server) AddCustomer(c *harvest.Customer) {
+	s.add("clients", 0, c)
+}
+
+// AddProject adds a project, setting its ID if empty.
+func (s *Server) AddProject(p *harvest.Project) {
+	s.add("projects", 0, p)
+}
+
+// AddContact adds a client contact, setting its ID if empty.
+func (s *Server) AddContact(c *harvest.Contact) {
+	s.add("contacts", 0, c)
+}
+
+// AddInvoice adds an invoice, setting its ID if empty. Amounts and line item
+// IDs are filled in, like Harvest does when creating an invoice.
+func (s *Server) AddInvoice(i *harvest.Invoice) {
+	s.add("invoices", 0, i)
+}
+
+// AddEstimate adds an estimate, setting its ID if empty. Amounts and line
+// item IDs are filled in, like Harvest does when creating an estimate.
+func (s *Server) AddEstimate(e *harvest.Estimate) {
+	s.add("estimates", 0, e)
+}
+
+// AddPayment adds a payment to an invoice, setting its ID if empty.
+func (s *Server) AddPayment(invoiceID int64, p *harvest.Payment) {
+	s.add("invoices/payments", invoiceID, p)
+}
+
+// AddExpenseCategory adds an expense category, setting its ID if empty.
+func (s *Server) AddExpenseCategory(c *harvest.ExpenseCategory) {
+	s.add("expense_categories", 0, c)
+}
+
+// AddTask adds a task, setting its ID if empty.
+func (s *Server) AddTask(t *harvest.Task) {
+	s.add("tasks", 0, t)
+}
+
+// AddTaskAssignment assigns a task to a project, setting its ID if empty.
+func (s *Server) AddTaskAssignment(projectID int64, a *harvest.TaskAssignment) {
+	s.add("projects/task_assignments", projectID, a)
+}
+
+// AddUser adds a user, setting its ID if empty.
+func (s *Server) AddUser(u *harvest.User) {
+	s.add("users", 0, u)
+}
+
+// AddRole adds a role, setting its ID if empty.
+func (s *Server) AddRole(r *harvest.Role) {
+	s.add("roles", 0, r)
+}
+
+// AddUserAssignment assigns a user to a project, setting its ID if empty.
+func (s *Server) AddUserAssignment(projectID int64, a *harvest.UserAssignment) {
+	s.add("projects/user_assignments", projectID, a)
+}
+
+// AddInvoiceItemCategory adds an invoice item category, setting its ID if
+// empty.
+func (s *Server) AddInvoiceItemCategory(c *harvest.InvoiceItemCategory) {
+	s.add("invoice_item_categories", 0, c)
+}
+
+// AddExpense adds an expense, setting its ID if empty.
+func (s *Server) AddExpense(e *harvest.Expense) {
+	s.add("expenses", 0, e)
+}
+
+// AddTimeEntry adds a time entry, setting its ID if empty.
+func (s *Server) AddTimeEntry(t *harvest.TimeEntry) {
+	s.add("time_entries", 0, t)
+}
+
+// AddAttachment adds a file to the client page of an invoice.
+func (s *Server) AddAttachment(invoiceID int64, filename string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attachments[invoiceID] = append(s.attachments[invoiceID], &attachment{
+		filename: filename,
+		data:     data,
+	})
+}
+
+// SetInvoicePDF sets the PDF document served for an invoice. By default a
+// small placeholder is served.
+func (s *Server) SetInvoicePDF(invoiceID int64, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.documents[invoiceID] = data
+}
+
+// Customer returns the stored client, or nil.
+func (s *Server) Customer(id int64) *harvest.Customer {
+	return get[harvest.Customer](s, "clients", id)
+}
+
+// Project returns the stored project, or nil.
+func (s *Server) Project(id int64) *harvest.Project {
+	return get[harvest.Project](s, "projects", id)
+}
+
+// Contact returns the stored contact, or nil.
+func (s *Server) Contact(id int64) *harvest.Contact {
+	return get[harvest.Contact](s, "contacts", id)
+}
+
+// Invoice returns the stored invoice, or nil.
+func (s *Server) Invoice(id int64) *harvest.Invoice {
+	return get[harvest.Invoice](s, "invoices", id)
+}
+
+// Estimate returns the stored estimate, or nil.
+func (s *Server) Estimate(id int64) *harvest.Estimate {
+	return get[harvest.Estimate](s, "estimates", id)
+}
+
+// Payments returns the payments of an invoice.
+func (s *Server) Payments(invoiceID int64) []*harvest.Payment {
+	return list[harvest.Payment](s, "invoices/payments", invoiceID)
+}
+
+// Expense returns the stored expense, or nil.
+func (s *Server) Expense(id int64) *harvest.Expense {
+	return get[harvest.Expense](s, "expenses", id)
+}
+
+// Task returns the stored task, or nil.
+func (s *Server) Task(id int64) *harvest.Task {
+	return get[harvest.Task](s, "tasks", id)
+}
+
+// TaskAssignment returns the stored task assignment, or nil.
+func (s *Server) TaskAssignment(id int64) *harvest.TaskAssignment {
+	return get[harvest.TaskAssignment](s, "projects/task_assignments", id)
+}
+
+// User returns the stored user, or nil.
+func (s *Server) User(id int64) *harvest.User {
+	return get[harvest.User](s, "users", id)
+}
+
+// Role returns the stored role, or nil.
+func (s *Server) Role(id int64) *harvest.Role {
+	return get[harvest.Role](s, "roles", id)
+}
+
+// UserAssignment returns the stored user assignment, or nil.
+func (s *Server) UserAssignment(id int64) *harvest.UserAssignment {
+	return get[harvest.UserAssignment](s, "projects/user_assignments", id)
+}
+
+// InvoiceItemCategory returns the stored invoice item category, or nil.
+func (s *Server) InvoiceItemCategory(id int64) *harvest.InvoiceItemCategory {
+	return get[harvest.InvoiceItemCategory](s, "invoice_item_categories", id)
+}
+
+// ExpenseCategory returns the stored expense category, or nil.
+func (s *Server) ExpenseCategory(id int64) *harvest.ExpenseCategory {
+	return get[harvest.ExpenseCategory](s, "expense_categories", id)
+}
+
+// TimeEntry returns the stored time entry, or nil.
+func (s *Server) TimeEntry(id int64) *harvest.TimeEntry {
+	return get[harvest.TimeEntry](s, "time_entries", id)
+}
+
+func (s *Server) add(name string, parent int64, obj any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collections[name]
+	rec := s.insert(c, parent, toRecord(obj))
+	if c.onCreate != nil {
+		if msg := c.onCreate(s, rec); msg != "" {
+			panic(fmt.Sprintf("harvesttest: invalid %s: %s", name, msg))
+		}
+	}
+
+	v := reflect.ValueOf(obj).Elem().FieldByName("ID")
+	if v.IsValid() && v.CanSet() {
+		v.SetInt(rec.id)
+	}
+}
+
+func get[T any](s *Server, name string, id int64) *T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.collections[name].find(id, -1)
+	if rec == nil {
+		return nil
+	}
+	return fromRecord[T](rec.data)
+}
+
+func list[T any](s *Server, name string, parent int64) []*T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*T, 0)
+	for _, rec := range s.collections[name].items {
+		if rec.parent == parent {
+			result = append(result, fromRecord[T](rec.data))
+		}
+	}
+	return result
+}
+
+// isEmpty reports whether v is missing, or a zero string or timestamp.
+func isEmpty(v any) bool {
+	s, ok := v.(string)
+	return v == nil || (ok && (s == "" || strings.HasPrefix(s, "0001-01-01")))
+}
+
+func toSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+func randomKey() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
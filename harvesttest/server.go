@@ -0,0 +1,610 @@
+// Package harvesttest provides an in-process fake of the Harvest v2 API, for
+// testing code that uses the harvest package without a real account.
+//
+// The fake keeps its data in memory, can be seeded with fixtures and can be
+// told to fail requests:
+//
+//	srv := harvesttest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddCustomer(&harvest.Customer{Name: "ACME"})
+//	srv.Inject(harvesttest.Fault{Method: "GET", Path: "/invoices", Status: 503})
+//
+//	hv, err := srv.Client()
+//
+// It aims to behave like the real API for the common cases (pagination,
+// filtering, nested objects, state changes), but is by no means complete.
+package harvesttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rubenv/harvest"
+)
+
+// Prefix under which the API is served, client pages are served from the
+// root (which is also the company base_uri).
+const apiPrefix = "/v2"
+
+type Server struct {
+	*httptest.Server
+
+	// Credentials expected in each API request.
+	AccountID int64
+	Token     string
+
+	// Default page size for listings.
+	PerPage int
+
+	// ID of the user that owns the token, used for /users/me. Defaults to
+	// the first user.
+	UserID int64
+
+	mu          sync.Mutex
+	nextID      int64
+	company     map[string]any
+	collections map[string]*collection
+	faults      []*Fault
+	documents   map[int64][]byte
+	attachments map[int64][]*attachment
+	receipts    map[int64]*attachment
+}
+
+// Fault describes an injected failure.
+type Fault struct {
+	// Method and path (relative to the API root, e.g. "/invoices/1") to fail.
+	// An empty Method matches all methods, Path matches by prefix.
+	Method string
+	Path   string
+
+	// Status code to answer with and the error details in the body.
+	Status      int
+	Code        string
+	Description string
+
+	// Value for the Retry-After header, in seconds.
+	RetryAfter int
+
+	// Number of requests to fail, zero means one.
+	Times int
+}
+
+type attachment struct {
+	filename string
+	data     []byte
+}
+
+// NewServer starts a fake Harvest server, call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		AccountID:   1,
+		Token:       "test-token",
+		PerPage:     100,
+		collections: make(map[string]*collection),
+		documents:   make(map[int64][]byte),
+		attachments: make(map[int64][]*attachment),
+		receipts:    make(map[int64]*attachment),
+	}
+	for _, c := range collections() {
+		s.collections[c.name] = c
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.company = map[string]any{
+		"base_uri":         s.URL,
+		"full_domain":      strings.TrimPrefix(s.URL, "http://"),
+		"name":             "Test Company",
+		"is_active":        true,
+		"week_start_day":   "Monday",
+		"time_format":      "hours_minutes",
+		"plan_type":        "sponsored",
+		"expense_feature":  true,
+		"invoice_feature":  true,
+		"estimate_feature": true,
+		"approval_feature": true,
+		"clock":            "24h",
+		"decimal_symbol":   ".",
+		"color_scheme":     "orange",
+	}
+	return s
+}
+
+// BaseURL returns the API root, to be passed to harvest.WithBaseURL.
+func (s *Server) BaseURL() string {
+	return s.URL + apiPrefix
+}
+
+// Client returns a harvest client that talks to this server. It doesn't do
+// client-side rate limiting and retries quickly, opts can override this.
+func (s *Server) Client(opts ...harvest.ClientOption) (*harvest.Client, error) {
+	opts = append([]harvest.ClientOption{
+		harvest.WithBaseURL(s.BaseURL()),
+		harvest.WithRateLimit(0, 0),
+		harvest.WithRetryPolicy(harvest.RetryPolicy{
+			MaxRetries: 3,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 10 * time.Millisecond,
+		}),
+	}, opts...)
+	return harvest.New(s.AccountID, s.Token, opts...)
+}
+
+// SetCompany replaces the company info. The base URI is always set to the
+// server URL, so invoice downloads keep working.
+func (s *Server) SetCompany(c *harvest.Company) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.company = toRecord(c)
+	s.company["base_uri"] = s.URL
+}
+
+// Inject makes matching requests fail, in the order they were injected.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Times <= 0 {
+		f.Times = 1
+	}
+	s.faults = append(s.faults, &f)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		s.serveClientPage(w, r)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+	if f := s.fault(r.Method, path); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+		}
+		writeError(w, f.Status, f.Code, f.Description)
+		return
+	}
+
+	if r.Header.Get("Harvest-Account-ID") != strconv.FormatInt(s.AccountID, 10) || r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, "invalid_token", "The access token provided is expired, revoked, malformed or invalid for other reasons.")
+		return
+	}
+
+	s.serveAPI(w, r, strings.Split(strings.Trim(path, "/"), "/"))
+}
+
+func (s *Server) fault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method == "" || f.Method == method) && strings.HasPrefix(path, f.Path) {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+			return f
+		}
+	}
+	return nil
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request, parts []string) {
+	if len(parts) == 1 && parts[0] == "company" && r.Method == "GET" {
+		writeJSON(w, http.StatusOK, s.company)
+		return
+	}
+
+	if parts[0] == "users" && len(parts) > 1 && parts[1] == "me" {
+		parts[1] = strconv.FormatInt(s.me(), 10)
+	}
+
+	if parts[0] == "reports" && len(parts) > 1 {
+		s.serveReport(w, r, strings.Join(parts[1:], "/"))
+		return
+	}
+
+	if name, ok := globalListings[parts[0]]; ok && len(parts) == 1 && r.Method == "GET" {
+		s.list(w, r, s.collections[name], -1)
+		return
+	}
+
+	// Walk the path: /{collection}[/{id}[/{collection}[/{id}]]][/{action}],
+	// nested collections are named after their parent, e.g. invoices/payments.
+	var parent *record
+	name := ""
+	for len(parts) > 0 {
+		name = strings.TrimPrefix(name+"/"+parts[0], "/")
+		c, ok := s.collections[name]
+		if !ok {
+			break
+		}
+
+		var parentID int64
+		if parent != nil {
+			parentID = parent.id
+		}
+
+		if len(parts) == 1 {
+			switch r.Method {
+			case "GET":
+				s.list(w, r, c, parentID)
+			case "POST":
+				s.create(w, r, c, parentID)
+			default:
+				writeError(w, http.StatusMethodNotAllowed, "", "")
+			}
+			return
+		}
+
+		if action, ok := c.listActions[parts[1]]; ok && len(parts) == 2 {
+			action(s, w, r, parent)
+			return
+		}
+
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			break
+		}
+		rec := c.find(id, parentID)
+		if rec == nil {
+			break
+		}
+
+		if len(parts) == 2 {
+			switch r.Method {
+			case "GET":
+				writeJSON(w, http.StatusOK, s.render(c, rec))
+			case "PATCH", "PUT":
+				s.update(w, r, c, rec)
+			case "DELETE":
+				s.delete(w, c, rec)
+			default:
+				writeError(w, http.StatusMethodNotAllowed, "", "")
+			}
+			return
+		}
+
+		if len(parts) == 3 {
+			if action, ok := c.actions[parts[2]]; ok {
+				action(s, w, r, rec)
+				return
+			}
+		}
+
+		parent = rec
+		parts = parts[2:]
+	}
+
+	writeError(w, http.StatusNotFound, "not_found", "The resource you requested could not be found")
+}
+
+// list writes a page of records, a parentID of -1 lists those of all parents.
+func (s *Server) list(w http.ResponseWriter, r *http.Request, c *collection, parentID int64) {
+	q := r.URL.Query()
+
+	recs := make([]*record, 0)
+	for _, rec := range c.items {
+		if (parentID >= 0 && rec.parent != parentID) || !c.matches(rec, q) {
+			continue
+		}
+		recs = append(recs, rec)
+	}
+
+	// Harvest returns the most recent objects first.
+	sort.SliceStable(recs, func(i, j int) bool {
+		if c.oldestFirst {
+			return recs[i].id < recs[j].id
+		}
+		return recs[i].id > recs[j].id
+	})
+
+	items := make([]map[string]any, 0, len(recs))
+	for _, rec := range recs {
+		items = append(items, s.render(c, rec))
+	}
+
+	perPage := s.PerPage
+	if v, err := strconv.Atoi(q.Get("per_page")); err == nil && v > 0 {
+		perPage = v
+	}
+	page := 1
+	if v, err := strconv.Atoi(q.Get("page")); err == nil && v > 0 {
+		page = v
+	}
+
+	total := len(items)
+	totalPages := max((total+perPage-1)/perPage, 1)
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+
+	pageURL := func(p int) any {
+		if p < 1 || p > totalPages {
+			return nil
+		}
+		v := url.Values{}
+		for k, vals := range q {
+			v[k] = vals
+		}
+		v.Set("page", strconv.Itoa(p))
+		v.Set("per_page", strconv.Itoa(perPage))
+		return fmt.Sprintf("%s%s?%s", s.URL, r.URL.Path, v.Encode())
+	}
+	pageNum := func(p int) any {
+		if p < 1 || p > totalPages {
+			return nil
+		}
+		return p
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		c.field:         items[start:end],
+		"per_page":      perPage,
+		"total_pages":   totalPages,
+		"total_entries": total,
+		"page":          page,
+		"next_page":     pageNum(page + 1),
+		"previous_page": pageNum(page - 1),
+		"links": map[string]any{
+			"first":    pageURL(1),
+			"next":     pageURL(page + 1),
+			"previous": pageURL(page - 1),
+			"last":     pageURL(totalPages),
+		},
+	})
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, c *collection, parentID int64) {
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+
+	rec := s.insert(c, parentID, data)
+	if c.onCreate != nil {
+		if msg := c.onCreate(s, rec); msg != "" {
+			c.remove(rec)
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"message": msg})
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.render(c, rec))
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, c *collection, rec *record) {
+	data, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "", err.Error())
+		return
+	}
+
+	s.resolveRefs(data)
+	if c.onUpdate != nil {
+		c.onUpdate(s, rec, data)
+	}
+	for k, v := range data {
+		rec.data[k] = v
+	}
+	rec.touch()
+	writeJSON(w, http.StatusOK, s.render(c, rec))
+}
+
+func (s *Server) delete(w http.ResponseWriter, c *collection, rec *record) {
+	if c.onDelete != nil {
+		if msg := c.onDelete(s, rec); msg != "" {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"message": msg})
+			return
+		}
+	}
+
+	c.remove(rec)
+	for name, child := range s.collections {
+		if strings.HasPrefix(name, c.name+"/") {
+			child.items = slices.DeleteFunc(child.items, func(r *record) bool {
+				return r.parent == rec.id
+			})
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// insert stores a new record, assigning an ID and timestamps.
+func (s *Server) insert(c *collection, parentID int64, data map[string]any) *record {
+	s.resolveRefs(data)
+
+	id := toInt(data["id"])
+	if id == 0 {
+		s.nextID++
+		id = s.nextID
+	} else if id > s.nextID {
+		s.nextID = id
+	}
+
+	for _, k := range []string{"created_at", "updated_at"} {
+		if v := str(data[k]); v == "" || strings.HasPrefix(v, "0001-01-01") {
+			data[k] = now()
+		}
+	}
+	data["id"] = id
+
+	rec := &record{id: id, parent: parentID, data: data}
+	c.items = append(c.items, rec)
+	return rec
+}
+
+// resolveRefs replaces foo_id fields by nested foo objects, like Harvest
+// returns them. Nested objects that were given directly (e.g. in fixtures)
+// get their missing fields filled in.
+func (s *Server) resolveRefs(data map[string]any) {
+	for _, ref := range refs {
+		obj, _ := data[ref.key].(map[string]any)
+		if v, ok := data[ref.key+"_id"]; ok {
+			delete(data, ref.key+"_id")
+			obj = map[string]any{"id": toInt(v)}
+		}
+		if obj == nil {
+			continue
+		}
+
+		if c := s.collections[ref.collection]; c != nil {
+			if rec := c.find(toInt(obj["id"]), -1); rec != nil {
+				for _, f := range ref.fields {
+					if isEmpty(obj[f]) {
+						obj[f] = field(rec, f)
+					}
+				}
+			}
+		}
+		data[ref.key] = obj
+	}
+}
+
+func (s *Server) render(c *collection, rec *record) map[string]any {
+	if c.render != nil {
+		return c.render(s, rec)
+	}
+	return rec.data
+}
+
+type record struct {
+	id     int64
+	parent int64
+	data   map[string]any
+}
+
+func (r *record) touch() {
+	r.data["updated_at"] = now()
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	body := map[string]any{"status": status}
+	if code != "" {
+		body["error"] = code
+	}
+	if description != "" {
+		body["error_description"] = description
+	}
+	writeJSON(w, status, body)
+}
+
+// readBody decodes a JSON or multipart request body into a map.
+func readBody(r *http.Request) (map[string]any, error) {
+	data := make(map[string]any)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err := r.ParseMultipartForm(32 << 20)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range r.MultipartForm.Value {
+			if n, err := strconv.ParseFloat(v[0], 64); err == nil && strings.HasSuffix(k, "_id") {
+				data[k] = n
+			} else if v[0] == "true" || v[0] == "false" {
+				data[k] = v[0] == "true"
+			} else {
+				data[k] = v[0]
+			}
+		}
+		for k, v := range r.MultipartForm.File {
+			f, err := v[0].Open()
+			if err != nil {
+				return nil, err
+			}
+			content, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			// The content is taken out by the resource that stores the
+			// file, it isn't part of the record.
+			data[k] = map[string]any{
+				"file_name":    v[0].Filename,
+				"file_size":    v[0].Size,
+				"content_type": v[0].Header.Get("Content-Type"),
+				"content":      content,
+			}
+		}
+		return data, nil
+	}
+
+	if r.ContentLength == 0 {
+		return data, nil
+	}
+	err := json.NewDecoder(r.Body).Decode(&data)
+	return data, err
+}
+
+// toRecord converts a harvest type to its JSON representation.
+func toRecord(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	r := make(map[string]any)
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// fromRecord converts a JSON representation back into a harvest type.
+func fromRecord[T any](r map[string]any) *T {
+	data, err := json.Marshal(r)
+	if err != nil {
+		panic(err)
+	}
+	result := new(T)
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func toInt(v any) int64 {
+	switch n := v.(type) {
+	case float64:
+		return int64(n)
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+package harvest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// ErrUnknownKind is returned for line items with a kind that doesn't match
+// an invoice item category, see WithKindValidation.
+var ErrUnknownKind = errors.New("Unknown invoice item category")
+
+// InvoiceItemCategory is a kind of invoice line item, e.g. Service or
+// Product.
+type InvoiceItemCategory struct {
+	// Unique ID for the invoice item category.
+	ID int64 `json:"id"`
+
+	// The name of the invoice item category.
+	Name string `json:"name"`
+
+	// Whether this invoice item category is used for billable hours when
+	// generating an invoice.
+	UseAsService bool `json:"use_as_service,omitempty"`
+
+	// Whether this invoice item category is used for expenses when
+	// generating an invoice.
+	UseAsExpense bool `json:"use_as_expense,omitempty"`
+
+	// Date and time the invoice item category was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the invoice item category was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateInvoiceItemCategory describes a new invoice item category.
+type CreateInvoiceItemCategory struct {
+	Name string `json:"name"`
+}
+
+// UpdateInvoiceItemCategory holds the fields to change on an invoice item
+// category.
+type UpdateInvoiceItemCategory struct {
+	Name string `json:"name,omitempty"`
+}
+
+func (hv *Client) InvoiceItemCategories(ctx context.Context, opts ...ListOption) iter.Seq2[*InvoiceItemCategory, error] {
+	return fetchIter[InvoiceItemCategory](ctx, hv, "invoice_item_categories", "invoice_item_categories", opts)
+}
+
+func (hv *Client) GetInvoiceItemCategory(ctx context.Context, id int64) (*InvoiceItemCategory, error) {
+	url := fmt.Sprintf("%s/invoice_item_categories/%d", hv.baseURL, id)
+	return doJSON[InvoiceItemCategory](ctx, hv, "GET", url, nil, http.StatusOK, "load invoice item category")
+}
+
+func (hv *Client) CreateInvoiceItemCategory(ctx context.Context, c *CreateInvoiceItemCategory) (*InvoiceItemCategory, error) {
+	url := fmt.Sprintf("%s/invoice_item_categories", hv.baseURL)
+	defer hv.resetKinds()
+	return doJSON[InvoiceItemCategory](ctx, hv, "POST", url, c, http.StatusCreated, "create invoice item category")
+}
+
+func (hv *Client) UpdateInvoiceItemCategory(ctx context.Context, id int64, c *UpdateInvoiceItemCategory) (*InvoiceItemCategory, error) {
+	url := fmt.Sprintf("%s/invoice_item_categories/%d", hv.baseURL, id)
+	defer hv.resetKinds()
+	return doJSON[InvoiceItemCategory](ctx, hv, "PATCH", url, c, http.StatusOK, "update invoice item category")
+}
+
+// DeleteInvoiceItemCategory deletes an invoice item category. Categories
+// that are used for services or expenses can't be deleted.
+func (hv *Client) DeleteInvoiceItemCategory(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/invoice_item_categories/%d", hv.baseURL, id)
+	defer hv.resetKinds()
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete invoice item category")
+}
+
+// checkKinds verifies that line items use known invoice item categories, when
+// enabled with WithKindValidation. The categories are loaded once and
+// cached.
+func (hv *Client) checkKinds(ctx context.Context, items []*LineItem) error {
+	if !hv.validateKinds {
+		return nil
+	}
+
+	hv.kindsMu.Lock()
+	defer hv.kindsMu.Unlock()
+
+	if hv.kinds == nil {
+		kinds := make(map[string]bool)
+		for c, err := range hv.InvoiceItemCategories(ctx) {
+			if err != nil {
+				return err
+			}
+			kinds[c.Name] = true
+		}
+		hv.kinds = kinds
+	}
+
+	for _, li := range items {
+		if li.Kind != "" && !li.Destroy && !hv.kinds[li.Kind] {
+			return fmt.Errorf("%w: %s", ErrUnknownKind, li.Kind)
+		}
+	}
+	return nil
+}
+
+// resetKinds drops the cached invoice item categories.
+func (hv *Client) resetKinds() {
+	hv.kindsMu.Lock()
+	defer hv.kindsMu.Unlock()
+
+	hv.kinds = nil
+}
@@ -0,0 +1,103 @@
+package harvest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/rubenv/harvest/harvesttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoiceItemCategories(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddInvoiceItemCategory(&harvest.InvoiceItemCategory{Name: "Service", UseAsService: true})
+
+	c, err := hv.CreateInvoiceItemCategory(ctx, &harvest.CreateInvoiceItemCategory{Name: "Hosting"})
+	assert.NoError(err)
+	assert.Equal("Hosting", c.Name)
+	assert.False(c.UseAsService)
+	assert.NotNil(c.Hv)
+
+	_, err = hv.CreateInvoiceItemCategory(ctx, &harvest.CreateInvoiceItemCategory{Name: "Hosting"})
+	assert.True(harvest.IsValidationError(err))
+
+	c, err = hv.UpdateInvoiceItemCategory(ctx, c.ID, &harvest.UpdateInvoiceItemCategory{Name: "Licenses"})
+	assert.NoError(err)
+	assert.Equal("Licenses", c.Name)
+
+	c, err = hv.GetInvoiceItemCategory(ctx, c.ID)
+	assert.NoError(err)
+	assert.Equal("Licenses", c.Name)
+
+	names := []string{}
+	for c, err := range hv.InvoiceItemCategories(ctx) {
+		assert.NoError(err)
+		names = append(names, c.Name)
+	}
+	assert.Equal([]string{"Licenses", "Service"}, names)
+
+	// Harvest rejects unknown kinds
+	_, err = hv.CreateInvoice(ctx, &harvest.Invoice{
+		ClientID:  1,
+		LineItems: []*harvest.LineItem{{Kind: "Servce", Quantity: 1, UnitPrice: 10}},
+	})
+	assert.True(harvest.IsValidationError(err))
+
+	// Categories in use can't be deleted
+	var service *harvest.InvoiceItemCategory
+	for c, err := range hv.InvoiceItemCategories(ctx) {
+		assert.NoError(err)
+		if c.Name == "Service" {
+			service = c
+		}
+	}
+	assert.True(harvest.IsValidationError(hv.DeleteInvoiceItemCategory(ctx, service.ID)))
+
+	assert.NoError(hv.DeleteInvoiceItemCategory(ctx, c.ID))
+	assert.Nil(srv.InvoiceItemCategory(c.ID))
+}
+
+func TestKindValidation(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, _ := newFake(t)
+
+	hv, err := srv.Client(harvest.WithKindValidation())
+	assert.NoError(err)
+
+	srv.AddCustomer(&harvest.Customer{Name: "ACME"})
+	srv.AddInvoiceItemCategory(&harvest.InvoiceItemCategory{Name: "Service"})
+
+	_, err = hv.CreateInvoice(ctx, &harvest.Invoice{
+		ClientID:  1,
+		LineItems: []*harvest.LineItem{{Kind: "Servce", Quantity: 1, UnitPrice: 10}},
+	})
+	assert.True(errors.Is(err, harvest.ErrUnknownKind))
+	assert.False(harvest.IsValidationError(err))
+	assert.Contains(err.Error(), "Servce")
+
+	// The categories are cached, so this doesn't hit the API
+	srv.Inject(harvesttest.Fault{Method: "GET", Path: "/invoice_item_categories", Status: 500})
+	i, err := hv.CreateInvoice(ctx, &harvest.Invoice{
+		ClientID:  1,
+		LineItems: []*harvest.LineItem{{Kind: "Service", Quantity: 1, UnitPrice: 10}},
+	})
+	assert.NoError(err)
+
+	err = i.AddLineItems(ctx, &harvest.LineItem{Kind: "Hosting", Quantity: 1, UnitPrice: 5})
+	assert.True(errors.Is(err, harvest.ErrUnknownKind))
+
+	// Creating a category resets the cache
+	_, err = hv.CreateInvoiceItemCategory(ctx, &harvest.CreateInvoiceItemCategory{Name: "Hosting"})
+	assert.NoError(err)
+	err = i.AddLineItems(ctx, &harvest.LineItem{Kind: "Hosting", Quantity: 1, UnitPrice: 5})
+	assert.Error(err) // Injected fault
+	err = i.AddLineItems(ctx, &harvest.LineItem{Kind: "Hosting", Quantity: 1, UnitPrice: 5})
+	assert.NoError(err)
+	assert.Len(i.LineItems, 2)
+}
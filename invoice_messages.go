@@ -0,0 +1,127 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type InvoiceMessage struct {
+	// Unique ID for the message.
+	ID int64 `json:"id"`
+
+	// Name of the user that created the message.
+	SentBy string `json:"sent_by"`
+
+	// Email of the user that created the message.
+	SentByEmail string `json:"sent_by_email"`
+
+	// Name of the user that the message was sent from.
+	SentFrom string `json:"sent_from"`
+
+	// Email of the user that the message was sent from.
+	SentFromEmail string `json:"sent_from_email"`
+
+	// The recipients of the message.
+	Recipients []*Recipient `json:"recipients"`
+
+	// The message subject.
+	Subject string `json:"subject"`
+
+	// The message body.
+	Body string `json:"body"`
+
+	// Whether to include a link to the client invoice in the message body.
+	IncludeLinkToClientInvoice bool `json:"include_link_to_client_invoice"`
+
+	// Whether to attach the invoice PDF to the message email.
+	AttachPDF bool `json:"attach_pdf"`
+
+	// Whether to email a copy of the message to the current user.
+	SendMeACopy bool `json:"send_me_a_copy"`
+
+	// Whether this is a thank you message.
+	ThankYou bool `json:"thank_you"`
+
+	// The type of invoice event that occurred with the message: send, close,
+	// draft, re-open or view. Empty for sent messages.
+	EventType string `json:"event_type"`
+
+	// Whether this is a reminder message.
+	Reminder bool `json:"reminder"`
+
+	// The date the reminder email will be sent.
+	SendReminderOn string `json:"send_reminder_on"`
+
+	// Date and time the message was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Date and time the message was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Hv *Client `json:"-"`
+}
+
+// MessageTemplate is the default subject and body Harvest uses for an invoice
+// message.
+type MessageTemplate struct {
+	InvoiceID int64  `json:"invoice_id"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+	Reminder  bool   `json:"reminder"`
+	ThankYou  bool   `json:"thank_you"`
+}
+
+// Close marks an open invoice as closed (written off).
+func (i *Invoice) Close(ctx context.Context) error {
+	return i.event(ctx, "close", InvoiceClosed, "close invoice")
+}
+
+// Reopen re-opens a closed invoice.
+func (i *Invoice) Reopen(ctx context.Context) error {
+	return i.event(ctx, "re-open", InvoiceOpen, "re-open invoice")
+}
+
+// MarkDraft turns an open invoice back into a draft.
+func (i *Invoice) MarkDraft(ctx context.Context) error {
+	return i.event(ctx, "draft", InvoiceDraft, "mark invoice as draft")
+}
+
+// event posts an event that moves the invoice to the given state, and updates
+// i.State to match once Harvest accepted it.
+func (i *Invoice) event(ctx context.Context, event string, state InvoiceState, action string) error {
+	url := fmt.Sprintf("%s/invoices/%d/messages", i.Hv.baseURL, i.ID)
+	err := i.Hv.call(ctx, "POST", url, eventRequest{
+		EventType: event,
+	}, http.StatusCreated, action)
+	if err != nil {
+		return err
+	}
+
+	i.State = string(state)
+	return nil
+}
+
+// Messages lists the messages and events of the invoice.
+func (i *Invoice) Messages(ctx context.Context) iter.Seq2[*InvoiceMessage, error] {
+	return fetchIter[InvoiceMessage, PageOption](ctx, i.Hv, "invoice_messages", fmt.Sprintf("invoices/%d/messages", i.ID), nil)
+}
+
+func (i *Invoice) DeleteMessage(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/invoices/%d/messages/%d", i.Hv.baseURL, i.ID, id)
+	return i.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete invoice message")
+}
+
+// PreviewMessage returns the subject and body Harvest would use for a new
+// message. Pass WithThankYou or WithReminder for those templates.
+func (i *Invoice) PreviewMessage(ctx context.Context, opts ...PreviewOption) (*MessageTemplate, error) {
+	v := &url.Values{}
+	for _, o := range opts {
+		o.apply(v)
+	}
+	url := fmt.Sprintf("%s/invoices/%d/messages/new?%s", i.Hv.baseURL, i.ID, v.Encode())
+	return doJSON[MessageTemplate](ctx, i.Hv, "GET", url, nil, http.StatusOK, "preview invoice message")
+}
@@ -0,0 +1,88 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvoiceMessages(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1, Name: "ACME"}, Number: "2024-001", Amount: 100})
+
+	inv, err := hv.FetchInvoices(ctx)
+	assert.NoError(err)
+	i := inv[0]
+
+	tpl, err := i.PreviewMessage(ctx)
+	assert.NoError(err)
+	assert.Equal(i.ID, tpl.InvoiceID)
+	assert.Contains(tpl.Subject, "2024-001")
+	assert.False(tpl.Reminder)
+
+	tpl, err = i.PreviewMessage(ctx, harvest.WithReminder())
+	assert.NoError(err)
+	assert.True(tpl.Reminder)
+
+	assert.True(harvest.IsValidationError(i.Close(ctx)))
+	assert.Equal("draft", i.State)
+	assert.NoError(i.MarkSent(ctx))
+	assert.Equal("open", i.State)
+	assert.NoError(i.Close(ctx))
+	assert.Equal("closed", i.State)
+	assert.Equal("closed", srv.Invoice(i.ID).State)
+	assert.NoError(i.Reopen(ctx))
+	assert.Equal("open", i.State)
+	assert.Equal("open", srv.Invoice(i.ID).State)
+	assert.NoError(i.MarkDraft(ctx))
+	assert.Equal("draft", i.State)
+	assert.Equal("draft", srv.Invoice(i.ID).State)
+
+	m, err := i.Send(ctx, "Invoice", "Please pay", []*harvest.Recipient{{Name: "Jane", Email: "jane@example.com"}}, &harvest.SendOptions{
+		CC:        []*harvest.Recipient{{Name: "Accounting", Email: "accounting@example.com"}},
+		AttachPDF: true,
+	})
+	assert.NoError(err)
+	assert.Equal("Invoice", m.Subject)
+	assert.Len(m.Recipients, 2)
+	assert.True(m.AttachPDF)
+	assert.False(m.IncludeLinkToClientInvoice)
+	assert.False(m.SendMeACopy)
+	assert.Equal("open", i.State)
+	assert.Equal("open", srv.Invoice(i.ID).State)
+
+	messages := []*harvest.InvoiceMessage{}
+	for m, err := range i.Messages(ctx) {
+		assert.NoError(err)
+		assert.NotNil(m.Hv)
+		messages = append(messages, m)
+	}
+	assert.Len(messages, 5)
+	assert.Equal("", messages[0].EventType)
+	assert.Equal("jane@example.com", messages[0].Recipients[0].Email)
+	assert.Equal("draft", messages[1].EventType)
+	assert.Equal("send", messages[4].EventType)
+
+	assert.NoError(i.DeleteMessage(ctx, messages[0].ID))
+	count := 0
+	for _, err := range i.Messages(ctx) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(4, count)
+
+	// Changing the defaults doesn't affect later sends
+	opts := harvest.DefaultSendOptions()
+	opts.SendMeACopy = false
+	m, err = i.Send(ctx, "Invoice", "Please pay", []*harvest.Recipient{{Name: "Jane", Email: "jane@example.com"}}, nil)
+	assert.NoError(err)
+	assert.True(m.SendMeACopy)
+	assert.True(m.IncludeLinkToClientInvoice)
+	assert.True(m.AttachPDF)
+	assert.True(harvest.DefaultSendOptions().SendMeACopy)
+}
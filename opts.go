@@ -2,13 +2,430 @@ package harvest
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/ratelimit"
 )
 
-type requestOption func(v *url.Values)
+// requestOption adds filters or other parameters to a request. Each listing
+// takes its own option type (InvoiceOption, TimeEntryOption, ...), which only
+// the options it supports implement, so passing a filter that doesn't apply
+// is a compile error.
+type requestOption interface {
+	apply(v *url.Values)
+}
+
+// param is a requestOption that sets query parameters, the option types
+// embed it.
+type param func(v *url.Values)
+
+func (p param) apply(v *url.Values) { p(v) }
+
+// InvoiceOption filters Client.Invoices: WithClientID, WithProjectID,
+// WithInvoiceState, WithFrom, WithTo, WithUpdatedSince and WithPerPage.
+type InvoiceOption interface {
+	requestOption
+	invoiceOption()
+}
+
+// EstimateOption filters Client.Estimates: WithClientID, WithEstimateState,
+// WithFrom, WithTo, WithUpdatedSince and WithPerPage.
+type EstimateOption interface {
+	requestOption
+	estimateOption()
+}
+
+// ExpenseOption filters Client.Expenses: WithClientID, WithProjectID,
+// WithUserID, WithIsBilled, WithApprovalStatus, WithFrom, WithTo,
+// WithUpdatedSince and WithPerPage.
+type ExpenseOption interface {
+	requestOption
+	expenseOption()
+}
+
+// TimeEntryOption filters Client.TimeEntries: WithClientID, WithProjectID,
+// WithUserID, WithTaskID, WithIsBilled, WithIsRunning, WithApprovalStatus,
+// WithFrom, WithTo, WithUpdatedSince and WithPerPage.
+type TimeEntryOption interface {
+	requestOption
+	timeEntryOption()
+}
+
+// ProjectOption filters Client.Projects: WithClientID, WithIsActive,
+// WithUpdatedSince and WithPerPage.
+type ProjectOption interface {
+	requestOption
+	projectOption()
+}
+
+// ContactOption filters Client.Contacts: WithClientID, WithUpdatedSince and
+// WithPerPage.
+type ContactOption interface {
+	requestOption
+	contactOption()
+}
+
+// UserAssignmentOption filters user assignments: WithUserID, WithIsActive,
+// WithUpdatedSince and WithPerPage.
+type UserAssignmentOption interface {
+	requestOption
+	userAssignmentOption()
+}
+
+// ActiveOption filters the listings of clients, users, tasks, expense
+// categories and task assignments: WithIsActive, WithUpdatedSince and
+// WithPerPage.
+type ActiveOption interface {
+	requestOption
+	activeOption()
+}
+
+// ListOption filters the listings of invoice item categories, payments and
+// project assignments: WithUpdatedSince and WithPerPage.
+type ListOption interface {
+	requestOption
+	listOption()
+}
+
+// PageOption is taken by listings that can't be filtered, like roles, rates
+// and reports: WithPerPage.
+type PageOption interface {
+	requestOption
+	pageOption()
+}
+
+// ProjectBudgetOption filters Client.ProjectBudget: WithIsActive and
+// WithPerPage.
+type ProjectBudgetOption interface {
+	requestOption
+	projectBudgetOption()
+}
+
+// PreviewOption picks the template for Invoice.PreviewMessage: WithThankYou
+// or WithReminder.
+type PreviewOption interface {
+	requestOption
+	previewOption()
+}
+
+type clientIDOption struct{ param }
 
-func WithClientID(id int64) requestOption {
-	return func(v *url.Values) {
+func (clientIDOption) invoiceOption()   {}
+func (clientIDOption) estimateOption()  {}
+func (clientIDOption) expenseOption()   {}
+func (clientIDOption) timeEntryOption() {}
+func (clientIDOption) projectOption()   {}
+func (clientIDOption) contactOption()   {}
+
+// WithClientID only lists objects of the given client. Applies to invoices,
+// estimates, expenses, time entries, projects and contacts.
+func WithClientID(id int64) clientIDOption {
+	return clientIDOption{func(v *url.Values) {
 		v.Set("client_id", fmt.Sprintf("%d", id))
+	}}
+}
+
+type projectIDOption struct{ param }
+
+func (projectIDOption) invoiceOption()   {}
+func (projectIDOption) expenseOption()   {}
+func (projectIDOption) timeEntryOption() {}
+
+// WithProjectID only lists objects of the given project. Applies to invoices,
+// expenses and time entries.
+func WithProjectID(id int64) projectIDOption {
+	return projectIDOption{func(v *url.Values) {
+		v.Set("project_id", fmt.Sprintf("%d", id))
+	}}
+}
+
+type userIDOption struct{ param }
+
+func (userIDOption) expenseOption()        {}
+func (userIDOption) timeEntryOption()      {}
+func (userIDOption) userAssignmentOption() {}
+
+// WithUserID only lists objects of the given user. Applies to expenses, time
+// entries and user assignments.
+func WithUserID(id int64) userIDOption {
+	return userIDOption{func(v *url.Values) {
+		v.Set("user_id", fmt.Sprintf("%d", id))
+	}}
+}
+
+type taskIDOption struct{ param }
+
+func (taskIDOption) timeEntryOption() {}
+
+// WithTaskID only lists time entries of the given task.
+func WithTaskID(id int64) taskIDOption {
+	return taskIDOption{func(v *url.Values) {
+		v.Set("task_id", fmt.Sprintf("%d", id))
+	}}
+}
+
+type isActiveOption struct{ param }
+
+func (isActiveOption) projectOption()        {}
+func (isActiveOption) userAssignmentOption() {}
+func (isActiveOption) activeOption()         {}
+func (isActiveOption) projectBudgetOption()  {}
+
+// WithIsActive only lists active (or archived) objects. Applies to clients,
+// projects, tasks, users, expense categories, task and user assignments and
+// the project budget report.
+func WithIsActive(active bool) isActiveOption {
+	return isActiveOption{func(v *url.Values) {
+		v.Set("is_active", strconv.FormatBool(active))
+	}}
+}
+
+type isBilledOption struct{ param }
+
+func (isBilledOption) expenseOption()   {}
+func (isBilledOption) timeEntryOption() {}
+
+// WithIsBilled only lists time entries or expenses that have (or haven't)
+// been invoiced.
+func WithIsBilled(billed bool) isBilledOption {
+	return isBilledOption{func(v *url.Values) {
+		v.Set("is_billed", strconv.FormatBool(billed))
+	}}
+}
+
+type isRunningOption struct{ param }
+
+func (isRunningOption) timeEntryOption() {}
+
+// WithIsRunning only lists time entries with a running (or stopped) timer.
+func WithIsRunning(running bool) isRunningOption {
+	return isRunningOption{func(v *url.Values) {
+		v.Set("is_running", strconv.FormatBool(running))
+	}}
+}
+
+// ApprovalStatus is the approval status of a time entry or expense.
+type ApprovalStatus string
+
+const (
+	ApprovalUnsubmitted ApprovalStatus = "unsubmitted"
+	ApprovalSubmitted   ApprovalStatus = "submitted"
+	ApprovalApproved    ApprovalStatus = "approved"
+)
+
+type approvalStatusOption struct{ param }
+
+func (approvalStatusOption) expenseOption()   {}
+func (approvalStatusOption) timeEntryOption() {}
+
+// WithApprovalStatus only lists time entries or expenses with the given
+// approval status.
+func WithApprovalStatus(status ApprovalStatus) approvalStatusOption {
+	return approvalStatusOption{func(v *url.Values) {
+		v.Set("approval_status", string(status))
+	}}
+}
+
+// InvoiceState is the state of an invoice.
+type InvoiceState string
+
+const (
+	InvoiceDraft  InvoiceState = "draft"
+	InvoiceOpen   InvoiceState = "open"
+	InvoicePaid   InvoiceState = "paid"
+	InvoiceClosed InvoiceState = "closed"
+)
+
+type invoiceStateOption struct{ param }
+
+func (invoiceStateOption) invoiceOption() {}
+
+// WithInvoiceState only lists invoices in the given state.
+func WithInvoiceState(state InvoiceState) invoiceStateOption {
+	return invoiceStateOption{func(v *url.Values) {
+		v.Set("state", string(state))
+	}}
+}
+
+// EstimateState is the state of an estimate.
+type EstimateState string
+
+const (
+	EstimateDraft    EstimateState = "draft"
+	EstimateSent     EstimateState = "sent"
+	EstimateAccepted EstimateState = "accepted"
+	EstimateDeclined EstimateState = "declined"
+)
+
+type estimateStateOption struct{ param }
+
+func (estimateStateOption) estimateOption() {}
+
+// WithEstimateState only lists estimates in the given state.
+func WithEstimateState(state EstimateState) estimateStateOption {
+	return estimateStateOption{func(v *url.Values) {
+		v.Set("state", string(state))
+	}}
+}
+
+type dateOption struct{ param }
+
+func (dateOption) invoiceOption()   {}
+func (dateOption) estimateOption()  {}
+func (dateOption) expenseOption()   {}
+func (dateOption) timeEntryOption() {}
+
+// WithFrom only lists objects on or after the given date: the issue date of
+// invoices and estimates, the spent date of time entries and expenses.
+func WithFrom(t time.Time) dateOption {
+	return dateOption{func(v *url.Values) {
+		v.Set("from", t.Format("2006-01-02"))
+	}}
+}
+
+// WithTo only lists objects on or before the given date, see WithFrom.
+func WithTo(t time.Time) dateOption {
+	return dateOption{func(v *url.Values) {
+		v.Set("to", t.Format("2006-01-02"))
+	}}
+}
+
+type updatedSinceOption struct{ param }
+
+func (updatedSinceOption) invoiceOption()        {}
+func (updatedSinceOption) estimateOption()       {}
+func (updatedSinceOption) expenseOption()        {}
+func (updatedSinceOption) timeEntryOption()      {}
+func (updatedSinceOption) projectOption()        {}
+func (updatedSinceOption) contactOption()        {}
+func (updatedSinceOption) userAssignmentOption() {}
+func (updatedSinceOption) activeOption()         {}
+func (updatedSinceOption) listOption()           {}
+
+// WithUpdatedSince only lists objects that have been updated since t. Applies
+// to all listings, except roles, rates and reports.
+func WithUpdatedSince(t time.Time) updatedSinceOption {
+	return updatedSinceOption{func(v *url.Values) {
+		v.Set("updated_since", t.UTC().Format(time.RFC3339))
+	}}
+}
+
+type perPageOption struct{ param }
+
+func (perPageOption) invoiceOption()        {}
+func (perPageOption) estimateOption()       {}
+func (perPageOption) expenseOption()        {}
+func (perPageOption) timeEntryOption()      {}
+func (perPageOption) projectOption()        {}
+func (perPageOption) contactOption()        {}
+func (perPageOption) userAssignmentOption() {}
+func (perPageOption) activeOption()         {}
+func (perPageOption) listOption()           {}
+func (perPageOption) pageOption()           {}
+func (perPageOption) projectBudgetOption()  {}
+
+// WithPerPage sets the number of objects fetched per request (1 to 2000).
+// All pages are still fetched when iterating. Applies to all listings.
+func WithPerPage(n int) perPageOption {
+	return perPageOption{func(v *url.Values) {
+		v.Set("per_page", strconv.Itoa(n))
+	}}
+}
+
+type templateOption struct{ param }
+
+func (templateOption) previewOption() {}
+
+// WithThankYou selects the thank you template in Invoice.PreviewMessage.
+func WithThankYou() templateOption {
+	return templateOption{func(v *url.Values) {
+		v.Set("thank_you", "true")
+	}}
+}
+
+// WithReminder selects the reminder template in Invoice.PreviewMessage.
+func WithReminder() templateOption {
+	return templateOption{func(v *url.Values) {
+		v.Set("reminder", "true")
+	}}
+}
+
+// ClientOption configures a Client, pass them to New.
+type ClientOption func(hv *Client)
+
+// WithBaseURL overrides the Harvest API endpoint, which defaults to
+// https://api.harvestapp.com/v2. Useful for proxies and test servers.
+func WithBaseURL(u string) ClientOption {
+	return func(hv *Client) {
+		hv.baseURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithHTTPClient makes the client use c for all requests, e.g. for custom TLS
+// settings, proxies or instrumentation.
+//
+// The default client keeps cookies and does not follow redirects, which is
+// needed to download invoice attachments. Custom clients may want to do the
+// same.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(hv *Client) {
+		hv.client = c
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request. Harvest
+// asks for the name of the application and a contact link or email address.
+func WithUserAgent(ua string) ClientOption {
+	return func(hv *Client) {
+		hv.userAgent = ua
+	}
+}
+
+// WithRateLimit allows at most requests requests per interval. The default
+// matches the Harvest limit of 100 requests per 15 seconds. A limit of zero
+// disables client-side rate limiting.
+func WithRateLimit(requests int, interval time.Duration) ClientOption {
+	return func(hv *Client) {
+		if requests <= 0 || interval <= 0 {
+			hv.bucket = nil
+			return
+		}
+		hv.bucket = ratelimit.NewBucket(interval/time.Duration(requests), int64(requests))
+	}
+}
+
+// WithRetryPolicy configures how failed requests are retried. Pass an empty
+// RetryPolicy to disable retries.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(hv *Client) {
+		hv.retry = p
+	}
+}
+
+// WithPrefetch makes listings fetch up to pages pages at the same time, rather
+// than one after another. Once the first page tells how many pages there are,
+// the next ones get fetched ahead while the current one is being iterated
+// over. Items are still returned in order and requests still go through the
+// rate limiter. A slow consumer holds up the fetching, no more than pages pages
+// are kept ahead.
+//
+// This applies to all listing iterators and to Pager.All, not to Pager.Next.
+func WithPrefetch(pages int) ClientOption {
+	return func(hv *Client) {
+		hv.prefetch = pages
+	}
+}
+
+// WithKindValidation checks the kind of line items against the invoice item
+// categories before invoices are created or updated, rather than relying on
+// Harvest to reject them. The categories are fetched when first needed and
+// cached, errors wrap ErrUnknownKind.
+func WithKindValidation() ClientOption {
+	return func(hv *Client) {
+		hv.validateKinds = true
 	}
 }
@@ -0,0 +1,363 @@
+package harvest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Pager walks through a listing one page at a time. Unlike the iterators, it
+// reports the totals Harvest sends and how far along it is, and after each
+// page it gives a cursor that can be stored to resume later on, e.g. from a
+// new process after a failed export.
+//
+// Harvest lists most objects newest first, so objects created while paging
+// shift the later pages: resuming may return a few objects twice. Filtering
+// on a fixed period (WithTo, WithUpdatedSince) keeps the pages stable.
+//
+// A Pager isn't safe for concurrent use.
+type Pager[T any] struct {
+	hv    *Client
+	field string
+	path  string
+
+	// URL of the next page, empty once the listing is done.
+	next    string
+	started bool
+
+	page         int
+	totalPages   int
+	totalEntries int
+	fetched      int
+}
+
+// cursor is the state of a Pager, as stored in Pager.Cursor.
+type cursor struct {
+	Next         string `json:"next"`
+	Page         int    `json:"page"`
+	TotalPages   int    `json:"total_pages"`
+	TotalEntries int    `json:"total_entries"`
+	Fetched      int    `json:"fetched"`
+}
+
+func newPager[T any, O requestOption](hv *Client, field, path string, opts []O) *Pager[T] {
+	v := &url.Values{}
+	for _, o := range opts {
+		o.apply(v)
+	}
+	return &Pager[T]{
+		hv:    hv,
+		field: field,
+		path:  path,
+		next:  fmt.Sprintf("%s/%s?%s", hv.baseURL, path, v.Encode()),
+	}
+}
+
+// InvoicePager pages through invoices, opts can be used to filter them.
+func (hv *Client) InvoicePager(opts ...InvoiceOption) *Pager[Invoice] {
+	return newPager[Invoice](hv, "invoices", "invoices", opts)
+}
+
+// EstimatePager pages through estimates.
+func (hv *Client) EstimatePager(opts ...EstimateOption) *Pager[Estimate] {
+	return newPager[Estimate](hv, "estimates", "estimates", opts)
+}
+
+// ExpensePager pages through expenses.
+func (hv *Client) ExpensePager(opts ...ExpenseOption) *Pager[Expense] {
+	return newPager[Expense](hv, "expenses", "expenses", opts)
+}
+
+// TimeEntryPager pages through time entries.
+func (hv *Client) TimeEntryPager(opts ...TimeEntryOption) *Pager[TimeEntry] {
+	return newPager[TimeEntry](hv, "time_entries", "time_entries", opts)
+}
+
+// ProjectPager pages through projects.
+func (hv *Client) ProjectPager(opts ...ProjectOption) *Pager[Project] {
+	return newPager[Project](hv, "projects", "projects", opts)
+}
+
+// CustomerPager pages through clients.
+func (hv *Client) CustomerPager(opts ...ActiveOption) *Pager[Customer] {
+	return newPager[Customer](hv, "clients", "clients", opts)
+}
+
+// ContactPager pages through contacts.
+func (hv *Client) ContactPager(opts ...ContactOption) *Pager[Contact] {
+	return newPager[Contact](hv, "contacts", "contacts", opts)
+}
+
+// UserPager pages through users.
+func (hv *Client) UserPager(opts ...ActiveOption) *Pager[User] {
+	return newPager[User](hv, "users", "users", opts)
+}
+
+// TaskPager pages through tasks.
+func (hv *Client) TaskPager(opts ...ActiveOption) *Pager[Task] {
+	return newPager[Task](hv, "tasks", "tasks", opts)
+}
+
+// More tells whether there are pages left to fetch.
+func (p *Pager[T]) More() bool {
+	return p.next != ""
+}
+
+// Next fetches the next page. It returns no items once the listing is done.
+// A failed page can be retried by calling Next again.
+func (p *Pager[T]) Next(ctx context.Context) ([]*T, error) {
+	if p.next == "" {
+		return nil, nil
+	}
+
+	r, err := fetchPage[T](ctx, p.hv, p.next, p.field)
+	if err != nil {
+		return nil, err
+	}
+
+	p.advance(r)
+	return r.Items, nil
+}
+
+// advance moves the pager past r.
+func (p *Pager[T]) advance(r *page[T]) {
+	p.started = true
+	p.next = r.Links.Next
+	p.page = r.Page
+	p.totalPages = r.TotalPages
+	p.totalEntries = r.TotalEntries
+	p.fetched += len(r.Items)
+}
+
+// All iterates over the remaining items, fetching pages as needed. The cursor
+// moves along per page, not per item.
+//
+// With WithPrefetch, later pages are fetched ahead once the number of pages is
+// known. The cursor still only moves past pages that have been iterated over.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for p.More() {
+			urls := p.pageURLs()
+			if p.hv.prefetch > 1 && len(urls) > 1 {
+				if !p.prefetch(ctx, urls, yield) {
+					return
+				}
+				continue
+			}
+
+			items, err := p.Next(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// pageURLs returns the URLs of the remaining pages, going by the last known
+// number of pages. Listings that don't page by number (or whose size isn't
+// known yet) return nil, they can only be followed one page at a time.
+func (p *Pager[T]) pageURLs() []string {
+	if !p.started || p.next == "" {
+		return nil
+	}
+
+	u, err := url.Parse(p.next)
+	if err != nil {
+		return nil
+	}
+	q := u.Query()
+	first, err := strconv.Atoi(q.Get("page"))
+	if err != nil {
+		return nil
+	}
+
+	urls := make([]string, 0)
+	for n := first; n <= p.totalPages; n++ {
+		q.Set("page", strconv.Itoa(n))
+		u.RawQuery = q.Encode()
+		urls = append(urls, u.String())
+	}
+	return urls
+}
+
+// prefetch fetches the pages at urls, keeping up to hv.prefetch of them in
+// flight or waiting to be iterated over, and yields their items in order. It
+// returns false when iteration should stop, because the caller is done or a
+// page failed.
+func (p *Pager[T]) prefetch(ctx context.Context, urls []string, yield func(*T, error) bool) bool {
+	type result struct {
+		page *page[T]
+		err  error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	results := make([]chan result, len(urls))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	slots := make(chan struct{}, p.hv.prefetch)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, u := range urls {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r, err := fetchPage[T](ctx, p.hv, u, p.field)
+				results[i] <- result{r, err}
+			}()
+		}
+	}()
+
+	for _, ch := range results {
+		var r result
+		select {
+		case r = <-ch:
+		case <-ctx.Done():
+			r.err = ctx.Err()
+		}
+		if r.err != nil {
+			yield(nil, r.err)
+			return false
+		}
+		<-slots
+
+		p.advance(r.page)
+		for _, item := range r.page.Items {
+			if !yield(item, nil) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Page returns the number of the last page fetched, starting at 1.
+func (p *Pager[T]) Page() int {
+	return p.page
+}
+
+// TotalPages returns the number of pages in the listing, as reported with the
+// last page fetched. It is 0 until the first page has been fetched.
+func (p *Pager[T]) TotalPages() int {
+	return p.totalPages
+}
+
+// TotalEntries returns the number of objects in the listing, as reported with
+// the last page fetched. It is 0 until the first page has been fetched.
+func (p *Pager[T]) TotalEntries() int {
+	return p.totalEntries
+}
+
+// Fetched returns the number of objects fetched so far, including those
+// fetched before resuming.
+func (p *Pager[T]) Fetched() int {
+	return p.fetched
+}
+
+// Cursor returns an opaque string that can be passed to Resume to continue
+// after the last page fetched.
+func (p *Pager[T]) Cursor() string {
+	next := p.next
+	if p.started && next == "" {
+		// Mark the listing as done, so resuming doesn't start over.
+		next = "-"
+	}
+
+	data, _ := json.Marshal(cursor{
+		// Stored relative to the API, so it survives a change of base URL.
+		Next:         strings.TrimPrefix(next, p.hv.baseURL+"/"),
+		Page:         p.page,
+		TotalPages:   p.totalPages,
+		TotalEntries: p.totalEntries,
+		Fetched:      p.fetched,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Resume continues from a cursor returned by Cursor. The cursor has to come
+// from the same listing, its filters replace the ones given to the pager.
+func (p *Pager[T]) Resume(s string) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("Invalid cursor: %w", err)
+	}
+
+	c := cursor{}
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return fmt.Errorf("Invalid cursor: %w", err)
+	}
+
+	next := ""
+	if c.Next != "-" {
+		next, err = p.resumeURL(c.Next)
+		if err != nil {
+			return err
+		}
+	}
+
+	p.next = next
+	p.started = c.Page > 0
+	p.page = c.Page
+	p.totalPages = c.TotalPages
+	p.totalEntries = c.TotalEntries
+	p.fetched = c.Fetched
+	return nil
+}
+
+// resumeURL turns the next page of a cursor back into a URL. Cursors are
+// stored outside of the client, so they may only point to this listing on
+// the API: anything else would get the credentials sent along.
+func (p *Pager[T]) resumeURL(next string) (string, error) {
+	u, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("Invalid cursor: %w", err)
+	}
+	if u.IsAbs() && !strings.HasPrefix(next, p.hv.baseURL+"/") {
+		return "", fmt.Errorf("Cursor points to %s, not to the API", u.Host)
+	}
+	if !u.IsAbs() {
+		next = p.hv.baseURL + "/" + next
+	}
+
+	u, err = url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("Invalid cursor: %w", err)
+	}
+	want, err := url.Parse(p.hv.baseURL + "/" + p.path)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != want.Scheme || u.Host != want.Host || u.User != nil {
+		return "", fmt.Errorf("Cursor points to %s, not to the API", u.Host)
+	}
+	if u.Path != want.Path {
+		return "", fmt.Errorf("Cursor is for %s, not %s", strings.TrimPrefix(u.Path, "/"), p.path)
+	}
+	return next, nil
+}
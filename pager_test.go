@@ -0,0 +1,197 @@
+package harvest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rubenv/harvest"
+	"github.com/rubenv/harvest/harvesttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPager(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+	srv.PerPage = 2
+
+	for i := 0; i < 5; i++ {
+		srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Invoice"})
+	}
+
+	p := hv.InvoicePager()
+	assert.True(p.More())
+	assert.Equal(0, p.TotalPages())
+
+	items, err := p.Next(ctx)
+	assert.NoError(err)
+	assert.Len(items, 2)
+	assert.Equal(1, p.Page())
+	assert.Equal(3, p.TotalPages())
+	assert.Equal(5, p.TotalEntries())
+	assert.Equal(2, p.Fetched())
+	assert.True(p.More())
+
+	// Failed pages can be retried
+	srv.Inject(harvesttest.Fault{Method: "GET", Path: "/invoices", Status: 500})
+	_, err = p.Next(ctx)
+	assert.Error(err)
+	assert.Equal(1, p.Page())
+
+	// Resume from a cursor in a new pager
+	p2 := hv.InvoicePager()
+	assert.NoError(p2.Resume(p.Cursor()))
+	assert.Equal(1, p2.Page())
+	assert.Equal(5, p2.TotalEntries())
+
+	ids := make([]int64, 0)
+	for i, err := range p2.All(ctx) {
+		assert.NoError(err)
+		ids = append(ids, i.ID)
+	}
+	assert.Len(ids, 3)
+	assert.NotContains(ids, items[0].ID)
+	assert.NotContains(ids, items[1].ID)
+	assert.False(p2.More())
+	assert.Equal(3, p2.Page())
+	assert.Equal(5, p2.Fetched())
+
+	// A finished listing stays finished
+	p3 := hv.InvoicePager()
+	assert.NoError(p3.Resume(p2.Cursor()))
+	assert.False(p3.More())
+	items, err = p3.Next(ctx)
+	assert.NoError(err)
+	assert.Empty(items)
+
+	// Cursors only fit their own listing
+	assert.Error(hv.ExpensePager().Resume(p.Cursor()))
+	assert.Error(hv.InvoicePager().Resume("garbage!"))
+
+	// Cursors can't send requests (and credentials) elsewhere
+	for _, next := range []string{
+		"http://other-host/x/invoices?page=2",
+		srv.BaseURL() + ".other-host/invoices?page=2",
+		"//other-host/invoices?page=2",
+		"../invoices?page=2",
+	} {
+		data, _ := json.Marshal(map[string]any{"next": next, "page": 1})
+		p4 := hv.InvoicePager()
+		assert.Error(p4.Resume(base64.RawURLEncoding.EncodeToString(data)), next)
+	}
+}
+
+func TestPagerFilters(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+	srv.PerPage = 1
+
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "One"})
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 2}, Subject: "Two"})
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Three"})
+
+	p := hv.InvoicePager(harvest.WithClientID(1))
+	_, err := p.Next(ctx)
+	assert.NoError(err)
+	assert.Equal(2, p.TotalEntries())
+
+	// The cursor keeps the filters
+	p2 := hv.InvoicePager()
+	assert.NoError(p2.Resume(p.Cursor()))
+	items, err := p2.Next(ctx)
+	assert.NoError(err)
+	if assert.Len(items, 1) {
+		assert.Equal("One", items[0].Subject)
+	}
+	assert.False(p2.More())
+}
+
+// slowTransport delays requests and records how many were in flight at once.
+type slowTransport struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	requests    int
+}
+
+func (t *slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.inFlight++
+	t.requests++
+	t.maxInFlight = max(t.maxInFlight, t.inFlight)
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.inFlight--
+		t.mu.Unlock()
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPrefetch(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, _ := newFake(t)
+	srv.PerPage = 1
+
+	for i := 0; i < 8; i++ {
+		srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Invoice"})
+	}
+
+	transport := &slowTransport{}
+	hv, err := srv.Client(harvest.WithPrefetch(3), harvest.WithHTTPClient(&http.Client{Transport: transport}))
+	assert.NoError(err)
+
+	// Items come in order, newest first
+	ids := make([]int64, 0)
+	for i, err := range hv.Invoices(ctx) {
+		assert.NoError(err)
+		ids = append(ids, i.ID)
+	}
+	assert.Len(ids, 8)
+	assert.IsNonIncreasing(ids)
+	assert.Equal(8, transport.requests)
+	assert.Equal(3, transport.maxInFlight)
+
+	// The cursor only moves past pages that were iterated over
+	p := hv.InvoicePager()
+	n := 0
+	for _, err := range p.All(ctx) {
+		assert.NoError(err)
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assert.Equal(3, p.Page())
+
+	rest := 0
+	p2 := hv.InvoicePager()
+	assert.NoError(p2.Resume(p.Cursor()))
+	for _, err := range p2.All(ctx) {
+		assert.NoError(err)
+		rest++
+	}
+	assert.Equal(5, rest)
+
+	// Failures stop the iteration, at the last page that got through
+	srv.Inject(harvesttest.Fault{Method: "GET", Path: "/invoices", Status: 404, Times: 10})
+	p3 := hv.InvoicePager()
+	assert.NoError(p3.Resume(p.Cursor()))
+	n = 0
+	for _, err := range p3.All(ctx) {
+		assert.Error(err)
+		n++
+	}
+	assert.Equal(1, n)
+	assert.Equal(3, p3.Page())
+}
@@ -0,0 +1,120 @@
+package harvest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type Payment struct {
+	// Unique ID for the payment.
+	ID int64 `json:"id"`
+
+	// The amount of the payment.
+	Amount float64 `json:"amount"`
+
+	// Date and time the payment was made.
+	PaidAt time.Time `json:"paid_at"`
+
+	// Date the payment was made.
+	PaidDate string `json:"paid_date"`
+
+	// The name of the person who recorded the payment.
+	RecordedBy string `json:"recorded_by"`
+
+	// The email of the person who recorded the payment.
+	RecordedByEmail string `json:"recorded_by_email"`
+
+	// Any notes associated with the payment.
+	Notes string `json:"notes"`
+
+	// Either the card authorization or PayPal transaction ID.
+	TransactionID string `json:"transaction_id"`
+
+	// The payment gateway id and name used to process the payment.
+	PaymentGateway PaymentGateway `json:"payment_gateway"`
+
+	// Date and time the payment was recorded.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Date and time the payment was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Hv *Client `json:"-"`
+
+	invoiceID int64
+}
+
+type PaymentGateway struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreatePayment describes a new payment. Set either PaidAt or PaidDate, the
+// payment is recorded for today if neither is set.
+type CreatePayment struct {
+	Amount   float64    `json:"amount"`
+	PaidAt   *time.Time `json:"paid_at,omitempty"`
+	PaidDate string     `json:"paid_date,omitempty"`
+	Notes    string     `json:"notes,omitempty"`
+}
+
+// Payments lists all payments of the invoice.
+func (i *Invoice) Payments(ctx context.Context, opts ...ListOption) iter.Seq2[*Payment, error] {
+	return func(yield func(*Payment, error) bool) {
+		for p, err := range fetchIter[Payment](ctx, i.Hv, "invoice_payments", fmt.Sprintf("invoices/%d/payments", i.ID), opts) {
+			if p != nil {
+				p.invoiceID = i.ID
+			}
+			if !yield(p, err) {
+				return
+			}
+		}
+	}
+}
+
+func (i *Invoice) GetPayments(ctx context.Context) ([]*Payment, error) {
+	result := make([]*Payment, 0)
+	for p, err := range i.Payments(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// CreatePayment records a payment for the invoice and returns it.
+func (i *Invoice) CreatePayment(ctx context.Context, p *CreatePayment) (*Payment, error) {
+	url := fmt.Sprintf("%s/invoices/%d/payments", i.Hv.baseURL, i.ID)
+	r, err := doJSON[Payment](ctx, i.Hv, "POST", url, p, http.StatusCreated, "add payment")
+	if err != nil {
+		return nil, err
+	}
+
+	r.invoiceID = i.ID
+	return r, nil
+}
+
+func (i *Invoice) AddPayment(ctx context.Context, amount float64, date time.Time, notes string) error {
+	_, err := i.CreatePayment(ctx, &CreatePayment{
+		Amount:   amount,
+		PaidDate: date.Format("2006-01-02"),
+		Notes:    notes,
+	})
+	return err
+}
+
+// Delete removes a payment that was recorded in error. Only works for
+// payments obtained through their invoice.
+func (p *Payment) Delete(ctx context.Context) error {
+	if p.invoiceID == 0 {
+		return errors.New("Unknown invoice for payment")
+	}
+
+	url := fmt.Sprintf("%s/invoices/%d/payments/%d", p.Hv.baseURL, p.invoiceID, p.ID)
+	return p.Hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete payment")
+}
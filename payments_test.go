@@ -0,0 +1,57 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayments(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+	srv.PerPage = 2
+
+	inv := &harvest.Invoice{Customer: &harvest.Customer{ID: 1, Name: "ACME"}, Amount: 1000, State: "open"}
+	srv.AddInvoice(inv)
+	for i := 0; i < 3; i++ {
+		srv.AddPayment(inv.ID, &harvest.Payment{Amount: 100})
+	}
+
+	i, err := hv.GetInvoice(ctx, inv.ID)
+	assert.NoError(err)
+	assert.Equal(700.0, i.DueAmount)
+
+	paidAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	p, err := i.CreatePayment(ctx, &harvest.CreatePayment{
+		Amount: 700,
+		PaidAt: &paidAt,
+		Notes:  "Bank transfer",
+	})
+	assert.NoError(err)
+	assert.Equal(700.0, p.Amount)
+	assert.True(paidAt.Equal(p.PaidAt))
+	assert.Equal("Bank transfer", p.Notes)
+	assert.Equal("paid", srv.Invoice(inv.ID).State)
+
+	// Follows pagination
+	payments, err := i.GetPayments(ctx)
+	assert.NoError(err)
+	assert.Len(payments, 4)
+
+	assert.NoError(p.Delete(ctx))
+	assert.Equal("open", srv.Invoice(inv.ID).State)
+	assert.Equal(700.0, srv.Invoice(inv.ID).DueAmount)
+
+	total := 0.0
+	for p, err := range i.Payments(ctx) {
+		assert.NoError(err)
+		total += p.Amount
+	}
+	assert.Equal(300.0, total)
+
+	assert.Error((&harvest.Payment{ID: 1, Hv: hv}).Delete(ctx))
+}
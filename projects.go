@@ -0,0 +1,170 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type Project struct {
+	// Unique ID for the project.
+	ID int64 `json:"id"`
+
+	// An object containing the project’s client id, name, and currency.
+	Customer *Customer `json:"client,omitempty"`
+
+	// Unique name for the project.
+	Name string `json:"name"`
+
+	// The code associated with the project.
+	Code string `json:"code"`
+
+	// Whether the project is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Whether the project is billable or not.
+	IsBillable bool `json:"is_billable,omitempty"`
+
+	// Whether the project is a fixed-fee project or not.
+	IsFixedFee bool `json:"is_fixed_fee,omitempty"`
+
+	// The method by which the project is invoiced: Project, Tasks, People or
+	// none.
+	BillBy string `json:"bill_by,omitempty"`
+
+	// Rate for projects billed by Project Hourly Rate.
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+
+	// The budget in hours for the project when budgeting by time.
+	Budget float64 `json:"budget,omitempty"`
+
+	// The method by which the project is budgeted: project (Hours Per
+	// Project), project_cost (Total Project Fees), task (Hours Per Task),
+	// task_fees (Fees Per Task), person (Hours Per Person) or none.
+	BudgetBy string `json:"budget_by,omitempty"`
+
+	// Option to have the budget reset every month.
+	BudgetIsMonthly bool `json:"budget_is_monthly,omitempty"`
+
+	// Whether Project Managers should be notified when the project goes over
+	// budget.
+	NotifyWhenOverBudget bool `json:"notify_when_over_budget,omitempty"`
+
+	// Percentage value used to trigger over budget email alerts.
+	OverBudgetNotificationPercentage float64 `json:"over_budget_notification_percentage,omitempty"`
+
+	// Date of last over budget notification. If none have been sent, this
+	// will be empty.
+	OverBudgetNotificationDate string `json:"over_budget_notification_date,omitempty"`
+
+	// Option to show project budget to all employees. Does not apply to Total
+	// Project Fee projects.
+	ShowBudgetToAll bool `json:"show_budget_to_all,omitempty"`
+
+	// The monetary budget for the project when budgeting by money.
+	CostBudget float64 `json:"cost_budget,omitempty"`
+
+	// Option for budget of Total Project Fees projects to include tracked
+	// expenses.
+	CostBudgetIncludeExpenses bool `json:"cost_budget_include_expenses,omitempty"`
+
+	// The amount you plan to invoice for the project. Only used by fixed-fee
+	// projects.
+	Fee float64 `json:"fee,omitempty"`
+
+	// Project notes.
+	Notes string `json:"notes,omitempty"`
+
+	// Date the project was started.
+	StartsOn string `json:"starts_on,omitempty"`
+
+	// Date the project will end.
+	EndsOn string `json:"ends_on,omitempty"`
+
+	// Date and time the project was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the project was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateProject describes a new project. ClientID, Name, IsBillable, BillBy
+// and BudgetBy are required.
+type CreateProject struct {
+	ClientID                         int64   `json:"client_id"`
+	Name                             string  `json:"name"`
+	Code                             string  `json:"code,omitempty"`
+	IsActive                         *bool   `json:"is_active,omitempty"`
+	IsBillable                       bool    `json:"is_billable"`
+	IsFixedFee                       bool    `json:"is_fixed_fee,omitempty"`
+	BillBy                           string  `json:"bill_by"`
+	HourlyRate                       float64 `json:"hourly_rate,omitempty"`
+	Budget                           float64 `json:"budget,omitempty"`
+	BudgetBy                         string  `json:"budget_by"`
+	BudgetIsMonthly                  bool    `json:"budget_is_monthly,omitempty"`
+	NotifyWhenOverBudget             bool    `json:"notify_when_over_budget,omitempty"`
+	OverBudgetNotificationPercentage float64 `json:"over_budget_notification_percentage,omitempty"`
+	ShowBudgetToAll                  bool    `json:"show_budget_to_all,omitempty"`
+	CostBudget                       float64 `json:"cost_budget,omitempty"`
+	CostBudgetIncludeExpenses        bool    `json:"cost_budget_include_expenses,omitempty"`
+	Fee                              float64 `json:"fee,omitempty"`
+	Notes                            string  `json:"notes,omitempty"`
+	StartsOn                         string  `json:"starts_on,omitempty"`
+	EndsOn                           string  `json:"ends_on,omitempty"`
+}
+
+// UpdateProject holds the fields to change on a project, nil and zero values
+// are left untouched.
+type UpdateProject struct {
+	ClientID                         int64    `json:"client_id,omitempty"`
+	Name                             string   `json:"name,omitempty"`
+	Code                             string   `json:"code,omitempty"`
+	IsActive                         *bool    `json:"is_active,omitempty"`
+	IsBillable                       *bool    `json:"is_billable,omitempty"`
+	IsFixedFee                       *bool    `json:"is_fixed_fee,omitempty"`
+	BillBy                           string   `json:"bill_by,omitempty"`
+	HourlyRate                       *float64 `json:"hourly_rate,omitempty"`
+	Budget                           *float64 `json:"budget,omitempty"`
+	BudgetBy                         string   `json:"budget_by,omitempty"`
+	BudgetIsMonthly                  *bool    `json:"budget_is_monthly,omitempty"`
+	NotifyWhenOverBudget             *bool    `json:"notify_when_over_budget,omitempty"`
+	OverBudgetNotificationPercentage *float64 `json:"over_budget_notification_percentage,omitempty"`
+	ShowBudgetToAll                  *bool    `json:"show_budget_to_all,omitempty"`
+	CostBudget                       *float64 `json:"cost_budget,omitempty"`
+	CostBudgetIncludeExpenses        *bool    `json:"cost_budget_include_expenses,omitempty"`
+	Fee                              *float64 `json:"fee,omitempty"`
+	Notes                            *string  `json:"notes,omitempty"`
+	StartsOn                         string   `json:"starts_on,omitempty"`
+	EndsOn                           string   `json:"ends_on,omitempty"`
+}
+
+func (hv *Client) Projects(ctx context.Context, opts ...ProjectOption) iter.Seq2[*Project, error] {
+	return fetchIter[Project](ctx, hv, "projects", "projects", opts)
+}
+
+func (hv *Client) GetProject(ctx context.Context, id int64) (*Project, error) {
+	url := fmt.Sprintf("%s/projects/%d", hv.baseURL, id)
+	return doJSON[Project](ctx, hv, "GET", url, nil, http.StatusOK, "load project")
+}
+
+func (hv *Client) CreateProject(ctx context.Context, p *CreateProject) (*Project, error) {
+	url := fmt.Sprintf("%s/projects", hv.baseURL)
+	return doJSON[Project](ctx, hv, "POST", url, p, http.StatusCreated, "create project")
+}
+
+func (hv *Client) UpdateProject(ctx context.Context, id int64, p *UpdateProject) (*Project, error) {
+	url := fmt.Sprintf("%s/projects/%d", hv.baseURL, id)
+	return doJSON[Project](ctx, hv, "PATCH", url, p, http.StatusOK, "update project")
+}
+
+// DeleteProject deletes a project, along with all time entries and expenses
+// tracked on it. Invoices for the project are kept. To keep the tracked time,
+// archive the project by setting IsActive to false instead.
+func (hv *Client) DeleteProject(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/projects/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete project")
+}
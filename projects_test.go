@@ -0,0 +1,60 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjects(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	srv.AddProject(&harvest.Project{Name: "Existing", Customer: acme, IsActive: true})
+
+	p, err := hv.CreateProject(ctx, &harvest.CreateProject{
+		ClientID:   acme.ID,
+		Name:       "Website",
+		Code:       "WEB",
+		IsBillable: true,
+		IsFixedFee: true,
+		BillBy:     "Project",
+		BudgetBy:   "project_cost",
+		CostBudget: 5000,
+		Fee:        4500,
+		StartsOn:   "2024-01-01",
+	})
+	assert.NoError(err)
+	assert.Equal("Website", p.Name)
+	assert.Equal("ACME", p.Customer.Name)
+	assert.True(p.IsActive)
+	assert.Equal(4500.0, p.Fee)
+	assert.NotNil(p.Hv)
+
+	_, err = hv.CreateProject(ctx, &harvest.CreateProject{ClientID: acme.ID, Name: "Website", BillBy: "none", BudgetBy: "none"})
+	assert.True(harvest.IsValidationError(err))
+
+	inactive := false
+	fee := 4000.0
+	p, err = hv.UpdateProject(ctx, p.ID, &harvest.UpdateProject{IsActive: &inactive, Fee: &fee})
+	assert.NoError(err)
+	assert.False(p.IsActive)
+	assert.Equal(4000.0, p.Fee)
+	assert.Equal("WEB", p.Code)
+
+	names := []string{}
+	for p, err := range hv.Projects(ctx, harvest.WithClientID(acme.ID)) {
+		assert.NoError(err)
+		names = append(names, p.Name)
+	}
+	assert.Equal([]string{"Website", "Existing"}, names)
+
+	assert.NoError(hv.DeleteProject(ctx, p.ID))
+	_, err = hv.GetProject(ctx, p.ID)
+	assert.True(harvest.IsNotFound(err))
+}
@@ -0,0 +1,165 @@
+package harvest
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"time"
+)
+
+// TimeReport is a row of a time report. Depending on the grouping, the
+// client, project, task or user fields are set.
+type TimeReport struct {
+	ClientID   int64  `json:"client_id,omitempty"`
+	ClientName string `json:"client_name,omitempty"`
+
+	ProjectID   int64  `json:"project_id,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+
+	TaskID   int64  `json:"task_id,omitempty"`
+	TaskName string `json:"task_name,omitempty"`
+
+	UserID         int64  `json:"user_id,omitempty"`
+	UserName       string `json:"user_name,omitempty"`
+	IsContractor   bool   `json:"is_contractor,omitempty"`
+	WeeklyCapacity int64  `json:"weekly_capacity,omitempty"`
+	AvatarURL      string `json:"avatar_url,omitempty"`
+
+	// The totals for the time tracked in the period.
+	TotalHours     float64 `json:"total_hours"`
+	BillableHours  float64 `json:"billable_hours"`
+	Currency       string  `json:"currency"`
+	BillableAmount float64 `json:"billable_amount"`
+}
+
+// ExpenseReport is a row of an expense report. Depending on the grouping,
+// the client, project, expense category or user fields are set.
+type ExpenseReport struct {
+	ClientID   int64  `json:"client_id,omitempty"`
+	ClientName string `json:"client_name,omitempty"`
+
+	ProjectID   int64  `json:"project_id,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+
+	ExpenseCategoryID   int64  `json:"expense_category_id,omitempty"`
+	ExpenseCategoryName string `json:"expense_category_name,omitempty"`
+
+	UserID       int64  `json:"user_id,omitempty"`
+	UserName     string `json:"user_name,omitempty"`
+	IsContractor bool   `json:"is_contractor,omitempty"`
+
+	// The totals for the expenses in the period.
+	TotalAmount    float64 `json:"total_amount"`
+	BillableAmount float64 `json:"billable_amount"`
+	Currency       string  `json:"currency"`
+}
+
+// UninvoicedReport is a row of the uninvoiced report, one per project.
+type UninvoicedReport struct {
+	ClientID    int64  `json:"client_id"`
+	ClientName  string `json:"client_name"`
+	ProjectID   int64  `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Currency    string `json:"currency"`
+
+	// The total hours for the period.
+	TotalHours float64 `json:"total_hours"`
+
+	// The billable hours for the period that haven't been invoiced yet.
+	UninvoicedHours float64 `json:"uninvoiced_hours"`
+
+	// The amount of billable expenses that haven't been invoiced yet.
+	UninvoicedExpenses float64 `json:"uninvoiced_expenses"`
+
+	// The amount of billable time and expenses that hasn't been invoiced
+	// yet.
+	UninvoicedAmount float64 `json:"uninvoiced_amount"`
+}
+
+// ProjectBudgetReport is a row of the project budget report, one per project.
+type ProjectBudgetReport struct {
+	ClientID    int64  `json:"client_id"`
+	ClientName  string `json:"client_name"`
+	ProjectID   int64  `json:"project_id"`
+	ProjectName string `json:"project_name"`
+
+	// The method by which the project is budgeted, see Project.BudgetBy.
+	BudgetBy        string `json:"budget_by"`
+	BudgetIsMonthly bool   `json:"budget_is_monthly"`
+	IsActive        bool   `json:"is_active"`
+
+	// The budget, in hours or money depending on BudgetBy, along with how
+	// much of it has been spent.
+	Budget          float64 `json:"budget"`
+	BudgetSpent     float64 `json:"budget_spent"`
+	BudgetRemaining float64 `json:"budget_remaining"`
+}
+
+// withPeriod limits a report to the given dates (inclusive).
+func withPeriod(from, to time.Time) requestOption {
+	return param(func(v *url.Values) {
+		v.Set("from", from.Format("20060102"))
+		v.Set("to", to.Format("20060102"))
+	})
+}
+
+func report[T any](ctx context.Context, hv *Client, path string, from, to time.Time, opts []PageOption) iter.Seq2[*T, error] {
+	all := []requestOption{withPeriod(from, to)}
+	for _, o := range opts {
+		all = append(all, o)
+	}
+	return fetchIter[T](ctx, hv, "results", "reports/"+path, all)
+}
+
+// TimeByClients reports the time tracked between from and to, per client.
+func (hv *Client) TimeByClients(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*TimeReport, error] {
+	return report[TimeReport](ctx, hv, "time/clients", from, to, opts)
+}
+
+// TimeByProjects reports the time tracked between from and to, per project.
+func (hv *Client) TimeByProjects(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*TimeReport, error] {
+	return report[TimeReport](ctx, hv, "time/projects", from, to, opts)
+}
+
+// TimeByTasks reports the time tracked between from and to, per task.
+func (hv *Client) TimeByTasks(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*TimeReport, error] {
+	return report[TimeReport](ctx, hv, "time/tasks", from, to, opts)
+}
+
+// TimeByTeam reports the time tracked between from and to, per user.
+func (hv *Client) TimeByTeam(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*TimeReport, error] {
+	return report[TimeReport](ctx, hv, "time/team", from, to, opts)
+}
+
+// ExpensesByClients reports the expenses between from and to, per client.
+func (hv *Client) ExpensesByClients(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*ExpenseReport, error] {
+	return report[ExpenseReport](ctx, hv, "expenses/clients", from, to, opts)
+}
+
+// ExpensesByProjects reports the expenses between from and to, per project.
+func (hv *Client) ExpensesByProjects(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*ExpenseReport, error] {
+	return report[ExpenseReport](ctx, hv, "expenses/projects", from, to, opts)
+}
+
+// ExpensesByCategories reports the expenses between from and to, per
+// expense category.
+func (hv *Client) ExpensesByCategories(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*ExpenseReport, error] {
+	return report[ExpenseReport](ctx, hv, "expenses/categories", from, to, opts)
+}
+
+// ExpensesByTeam reports the expenses between from and to, per user.
+func (hv *Client) ExpensesByTeam(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*ExpenseReport, error] {
+	return report[ExpenseReport](ctx, hv, "expenses/team", from, to, opts)
+}
+
+// Uninvoiced reports, per project, the billable time and expenses between
+// from and to that haven't been invoiced yet.
+func (hv *Client) Uninvoiced(ctx context.Context, from, to time.Time, opts ...PageOption) iter.Seq2[*UninvoicedReport, error] {
+	return report[UninvoicedReport](ctx, hv, "uninvoiced", from, to, opts)
+}
+
+// ProjectBudget reports the budget of each project and how much of it has
+// been spent.
+func (hv *Client) ProjectBudget(ctx context.Context, opts ...ProjectBudgetOption) iter.Seq2[*ProjectBudgetReport, error] {
+	return fetchIter[ProjectBudgetReport](ctx, hv, "results", "reports/project_budget", opts)
+}
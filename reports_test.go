@@ -0,0 +1,140 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReports(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	acme := &harvest.Customer{Name: "ACME", Currency: "EUR"}
+	srv.AddCustomer(acme)
+	globex := &harvest.Customer{Name: "Globex", Currency: "USD"}
+	srv.AddCustomer(globex)
+	web := &harvest.Project{Customer: &harvest.Customer{ID: acme.ID}, Name: "Website", BudgetBy: "project", Budget: 10}
+	srv.AddProject(web)
+	app := &harvest.Project{Customer: &harvest.Customer{ID: globex.ID}, Name: "App", BudgetBy: "project_cost", CostBudget: 1000, CostBudgetIncludeExpenses: true}
+	srv.AddProject(app)
+	dev := &harvest.Task{Name: "Development"}
+	srv.AddTask(dev)
+	john := &harvest.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", IsContractor: true}
+	srv.AddUser(john)
+	meals := &harvest.ExpenseCategory{Name: "Meals"}
+	srv.AddExpenseCategory(meals)
+
+	entry := func(p *harvest.Project, date string, hours float64, billable bool) {
+		srv.AddTimeEntry(&harvest.TimeEntry{
+			Project:      &harvest.Project{ID: p.ID},
+			Task:         &harvest.Task{ID: dev.ID},
+			User:         &harvest.UserRef{ID: john.ID},
+			SpentDate:    date,
+			Hours:        hours,
+			Billable:     billable,
+			BillableRate: 100,
+		})
+	}
+	entry(web, "2024-01-10", 2, true)
+	entry(web, "2024-01-11", 1.5, false)
+	entry(app, "2024-01-12", 3, true)
+	entry(app, "2024-02-01", 8, true) // Outside of the period
+	srv.AddExpense(&harvest.Expense{
+		Project:         &harvest.Project{ID: app.ID},
+		ExpenseCategory: &harvest.ExpenseCategory{ID: meals.ID},
+		User:            &harvest.UserRef{ID: john.ID},
+		SpentDate:       "2024-01-15",
+		TotalCost:       50,
+		Billable:        true,
+	})
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	clients := []*harvest.TimeReport{}
+	for r, err := range hv.TimeByClients(ctx, from, to) {
+		assert.NoError(err)
+		clients = append(clients, r)
+	}
+	if assert.Len(clients, 2) {
+		assert.Equal("ACME", clients[0].ClientName)
+		assert.Equal(3.5, clients[0].TotalHours)
+		assert.Equal(2.0, clients[0].BillableHours)
+		assert.Equal(200.0, clients[0].BillableAmount)
+		assert.Equal("EUR", clients[0].Currency)
+		assert.Equal("Globex", clients[1].ClientName)
+		assert.Equal("USD", clients[1].Currency)
+	}
+
+	for r, err := range hv.TimeByProjects(ctx, from, to) {
+		assert.NoError(err)
+		assert.NotEmpty(r.ProjectName)
+		assert.NotEmpty(r.ClientName)
+	}
+
+	tasks := 0
+	for r, err := range hv.TimeByTasks(ctx, from, to) {
+		assert.NoError(err)
+		assert.Equal("Development", r.TaskName)
+		tasks++
+	}
+	assert.Equal(2, tasks) // One per currency
+
+	for r, err := range hv.TimeByTeam(ctx, from, to) {
+		assert.NoError(err)
+		assert.Equal("John Doe", r.UserName)
+		assert.True(r.IsContractor)
+	}
+
+	categories := []*harvest.ExpenseReport{}
+	for r, err := range hv.ExpensesByCategories(ctx, from, to) {
+		assert.NoError(err)
+		categories = append(categories, r)
+	}
+	if assert.Len(categories, 1) {
+		assert.Equal("Meals", categories[0].ExpenseCategoryName)
+		assert.Equal(50.0, categories[0].TotalAmount)
+		assert.Equal(50.0, categories[0].BillableAmount)
+		assert.Equal("USD", categories[0].Currency)
+	}
+
+	uninvoiced := map[string]*harvest.UninvoicedReport{}
+	for r, err := range hv.Uninvoiced(ctx, from, to) {
+		assert.NoError(err)
+		uninvoiced[r.ProjectName] = r
+	}
+	if assert.Len(uninvoiced, 2) {
+		assert.Equal(3.5, uninvoiced["Website"].TotalHours)
+		assert.Equal(2.0, uninvoiced["Website"].UninvoicedHours)
+		assert.Equal(200.0, uninvoiced["Website"].UninvoicedAmount)
+		assert.Equal(50.0, uninvoiced["App"].UninvoicedExpenses)
+		assert.Equal(350.0, uninvoiced["App"].UninvoicedAmount)
+	}
+
+	budgets := map[string]*harvest.ProjectBudgetReport{}
+	for r, err := range hv.ProjectBudget(ctx) {
+		assert.NoError(err)
+		budgets[r.ProjectName] = r
+	}
+	if assert.Len(budgets, 2) {
+		assert.Equal(10.0, budgets["Website"].Budget)
+		assert.Equal(3.5, budgets["Website"].BudgetSpent)
+		assert.Equal(6.5, budgets["Website"].BudgetRemaining)
+		assert.Equal(1150.0, budgets["App"].BudgetSpent)
+		assert.Equal(-150.0, budgets["App"].BudgetRemaining)
+	}
+
+	// Reports are paged like any other listing
+	srv.PerPage = 1
+	count := 0
+	for _, err := range hv.TimeByClients(ctx, from, to) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(2, count)
+}
@@ -0,0 +1,137 @@
+package harvest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Requests are retried on 429 Too Many Requests, 502 Bad Gateway, 503
+// Service Unavailable, 504 Gateway Timeout and on transient network errors,
+// using exponential backoff with jitter. A Retry-After header sent by Harvest
+// takes precedence when it asks for a longer wait.
+//
+// Since Harvest doesn't process rate limited requests, these are always
+// retried. Other failures are only retried for idempotent requests (or when
+// no connection could be made), so a POST that might have been handled won't
+// be sent twice.
+type RetryPolicy struct {
+	// Maximum number of retries, zero disables retrying.
+	MaxRetries int
+
+	// Backoff before the first retry, doubled for each subsequent retry.
+	MinBackoff time.Duration
+
+	// Upper limit for the backoff.
+	MaxBackoff time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 30 * time.Second,
+}
+
+// backoff returns the delay before the given retry (starting at 0).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Jitter: wait somewhere between half and the full backoff.
+	return d/2 + rand.N(d/2+1)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransient reports whether err is a network error that might go away:
+// timeouts, refused or reset connections and connections closed halfway.
+// Anything else (bad certificates, unsupported schemes, malformed URLs, ...)
+// fails the same way on every attempt.
+func isTransient(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var authErr x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &certErr) || errors.As(err, &authErr) || errors.As(err, &hostErr) || errors.As(err, &invalidErr) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// notSent reports whether err happened before the request reached Harvest.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry decides whether the outcome of a request warrants another
+// attempt and returns the delay requested by the server, if any.
+func shouldRetry(req *http.Request, resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		if req.Context().Err() != nil || !isTransient(err) {
+			return false, 0
+		}
+		return isIdempotent(req.Method) || notSent(err), 0
+	}
+
+	if !isRetryableStatus(resp.StatusCode) {
+		return false, 0
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && !isIdempotent(req.Method) {
+		return false, 0
+	}
+	return true, parseRetryAfter(resp.Header.Get("Retry-After"))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// Role is a business role (e.g. "Designer"), used to group users.
+type Role struct {
+	// Unique ID for the role.
+	ID int64 `json:"id"`
+
+	// The name of the role.
+	Name string `json:"name"`
+
+	// The IDs of the users assigned to this role.
+	UserIDs []int64 `json:"user_ids"`
+
+	// Date and time the role was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the role was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateRole describes a new role, only Name is required.
+type CreateRole struct {
+	Name    string  `json:"name"`
+	UserIDs []int64 `json:"user_ids,omitempty"`
+}
+
+// UpdateRole holds the fields to change on a role, nil and zero values are
+// left untouched. UserIDs replaces the users of the role, point it to an
+// empty slice to remove them all.
+type UpdateRole struct {
+	Name    string   `json:"name,omitempty"`
+	UserIDs *[]int64 `json:"user_ids,omitempty"`
+}
+
+func (hv *Client) Roles(ctx context.Context, opts ...PageOption) iter.Seq2[*Role, error] {
+	return fetchIter[Role](ctx, hv, "roles", "roles", opts)
+}
+
+func (hv *Client) GetRole(ctx context.Context, id int64) (*Role, error) {
+	url := fmt.Sprintf("%s/roles/%d", hv.baseURL, id)
+	return doJSON[Role](ctx, hv, "GET", url, nil, http.StatusOK, "load role")
+}
+
+func (hv *Client) CreateRole(ctx context.Context, r *CreateRole) (*Role, error) {
+	url := fmt.Sprintf("%s/roles", hv.baseURL)
+	return doJSON[Role](ctx, hv, "POST", url, r, http.StatusCreated, "create role")
+}
+
+func (hv *Client) UpdateRole(ctx context.Context, id int64, r *UpdateRole) (*Role, error) {
+	url := fmt.Sprintf("%s/roles/%d", hv.baseURL, id)
+	return doJSON[Role](ctx, hv, "PATCH", url, r, http.StatusOK, "update role")
+}
+
+func (hv *Client) DeleteRole(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/roles/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete role")
+}
@@ -0,0 +1,52 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoles(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	u := &harvest.User{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+	srv.AddUser(u)
+	srv.AddRole(&harvest.Role{Name: "Designer"})
+
+	r, err := hv.CreateRole(ctx, &harvest.CreateRole{Name: "Developer", UserIDs: []int64{u.ID}})
+	assert.NoError(err)
+	assert.Equal("Developer", r.Name)
+	assert.Equal([]int64{u.ID}, r.UserIDs)
+	assert.NotNil(r.Hv)
+
+	_, err = hv.CreateRole(ctx, &harvest.CreateRole{})
+	assert.True(harvest.IsValidationError(err))
+
+	r, err = hv.UpdateRole(ctx, r.ID, &harvest.UpdateRole{Name: "Engineer"})
+	assert.NoError(err)
+	assert.Equal("Engineer", r.Name)
+	assert.Equal([]int64{u.ID}, r.UserIDs)
+
+	r, err = hv.UpdateRole(ctx, r.ID, &harvest.UpdateRole{UserIDs: &[]int64{}})
+	assert.NoError(err)
+	assert.Empty(r.UserIDs)
+
+	r, err = hv.GetRole(ctx, r.ID)
+	assert.NoError(err)
+	assert.Equal("Engineer", r.Name)
+	assert.Empty(r.UserIDs)
+
+	names := []string{}
+	for r, err := range hv.Roles(ctx) {
+		assert.NoError(err)
+		names = append(names, r.Name)
+	}
+	assert.Equal([]string{"Engineer", "Designer"}, names)
+
+	assert.NoError(hv.DeleteRole(ctx, r.ID))
+	assert.Nil(srv.Role(r.ID))
+}
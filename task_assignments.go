@@ -0,0 +1,92 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// TaskAssignment links a task to a project.
+type TaskAssignment struct {
+	// Unique ID for the task assignment.
+	ID int64 `json:"id"`
+
+	// An object containing the id, name, and code of the associated project.
+	Project *Project `json:"project,omitempty"`
+
+	// An object containing the id and name of the associated task.
+	Task *Task `json:"task,omitempty"`
+
+	// Whether the task assignment is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Whether the task assignment is billable or not.
+	Billable bool `json:"billable,omitempty"`
+
+	// Rate used when the project’s bill_by is Tasks.
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+
+	// Budget used when the project’s budget_by is task or task_fees.
+	Budget float64 `json:"budget,omitempty"`
+
+	// Date and time the task assignment was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the task assignment was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateTaskAssignment describes a new task assignment, only TaskID is
+// required.
+type CreateTaskAssignment struct {
+	TaskID     int64   `json:"task_id"`
+	IsActive   *bool   `json:"is_active,omitempty"`
+	Billable   *bool   `json:"billable,omitempty"`
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+	Budget     float64 `json:"budget,omitempty"`
+}
+
+// UpdateTaskAssignment holds the fields to change on a task assignment, nil
+// values are left untouched.
+type UpdateTaskAssignment struct {
+	IsActive   *bool    `json:"is_active,omitempty"`
+	Billable   *bool    `json:"billable,omitempty"`
+	HourlyRate *float64 `json:"hourly_rate,omitempty"`
+	Budget     *float64 `json:"budget,omitempty"`
+}
+
+// TaskAssignments lists the task assignments of all projects.
+func (hv *Client) TaskAssignments(ctx context.Context, opts ...ActiveOption) iter.Seq2[*TaskAssignment, error] {
+	return fetchIter[TaskAssignment](ctx, hv, "task_assignments", "task_assignments", opts)
+}
+
+// ProjectTaskAssignments lists the task assignments of a project.
+func (hv *Client) ProjectTaskAssignments(ctx context.Context, projectID int64, opts ...ActiveOption) iter.Seq2[*TaskAssignment, error] {
+	return fetchIter[TaskAssignment](ctx, hv, "task_assignments", fmt.Sprintf("projects/%d/task_assignments", projectID), opts)
+}
+
+func (hv *Client) GetTaskAssignment(ctx context.Context, projectID, id int64) (*TaskAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/task_assignments/%d", hv.baseURL, projectID, id)
+	return doJSON[TaskAssignment](ctx, hv, "GET", url, nil, http.StatusOK, "load task assignment")
+}
+
+func (hv *Client) CreateTaskAssignment(ctx context.Context, projectID int64, a *CreateTaskAssignment) (*TaskAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/task_assignments", hv.baseURL, projectID)
+	return doJSON[TaskAssignment](ctx, hv, "POST", url, a, http.StatusCreated, "create task assignment")
+}
+
+func (hv *Client) UpdateTaskAssignment(ctx context.Context, projectID, id int64, a *UpdateTaskAssignment) (*TaskAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/task_assignments/%d", hv.baseURL, projectID, id)
+	return doJSON[TaskAssignment](ctx, hv, "PATCH", url, a, http.StatusOK, "update task assignment")
+}
+
+// DeleteTaskAssignment removes a task from a project. It fails when time has
+// been tracked on the task within that project.
+func (hv *Client) DeleteTaskAssignment(ctx context.Context, projectID, id int64) error {
+	url := fmt.Sprintf("%s/projects/%d/task_assignments/%d", hv.baseURL, projectID, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete task assignment")
+}
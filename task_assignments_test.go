@@ -0,0 +1,80 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskAssignments(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddCustomer(&harvest.Customer{Name: "ACME"})
+	p1 := &harvest.Project{Customer: &harvest.Customer{ID: 1}, Name: "Website", Code: "WEB"}
+	srv.AddProject(p1)
+	p2 := &harvest.Project{Customer: &harvest.Customer{ID: 1}, Name: "App"}
+	srv.AddProject(p2)
+	dev := &harvest.Task{Name: "Development", DefaultHourlyRate: 100}
+	srv.AddTask(dev)
+	design := &harvest.Task{Name: "Design"}
+	srv.AddTask(design)
+	srv.AddTaskAssignment(p2.ID, &harvest.TaskAssignment{Task: &harvest.Task{ID: dev.ID}})
+
+	a, err := hv.CreateTaskAssignment(ctx, p1.ID, &harvest.CreateTaskAssignment{TaskID: dev.ID, Budget: 40})
+	assert.NoError(err)
+	assert.Equal(p1.ID, a.Project.ID)
+	assert.Equal("WEB", a.Project.Code)
+	assert.Equal("Development", a.Task.Name)
+	assert.Equal(100.0, a.HourlyRate)
+	assert.Equal(40.0, a.Budget)
+	assert.True(a.Billable)
+	assert.True(a.IsActive)
+
+	_, err = hv.CreateTaskAssignment(ctx, p1.ID, &harvest.CreateTaskAssignment{TaskID: dev.ID})
+	assert.True(harvest.IsValidationError(err))
+
+	notBillable := false
+	b, err := hv.CreateTaskAssignment(ctx, p1.ID, &harvest.CreateTaskAssignment{TaskID: design.ID, Billable: &notBillable})
+	assert.NoError(err)
+	assert.False(b.Billable)
+
+	rate := 120.0
+	a, err = hv.UpdateTaskAssignment(ctx, p1.ID, a.ID, &harvest.UpdateTaskAssignment{HourlyRate: &rate})
+	assert.NoError(err)
+	assert.Equal(120.0, a.HourlyRate)
+	assert.Equal(40.0, a.Budget)
+
+	a, err = hv.GetTaskAssignment(ctx, p1.ID, a.ID)
+	assert.NoError(err)
+	assert.Equal(120.0, a.HourlyRate)
+
+	// Assignments belong to their project
+	_, err = hv.GetTaskAssignment(ctx, p2.ID, a.ID)
+	assert.True(harvest.IsNotFound(err))
+
+	tasks := []string{}
+	for a, err := range hv.ProjectTaskAssignments(ctx, p1.ID) {
+		assert.NoError(err)
+		tasks = append(tasks, a.Task.Name)
+	}
+	assert.Equal([]string{"Design", "Development"}, tasks)
+
+	count := 0
+	for a, err := range hv.TaskAssignments(ctx) {
+		assert.NoError(err)
+		assert.NotNil(a.Project)
+		count++
+	}
+	assert.Equal(3, count)
+
+	// Can't delete assignments with tracked time
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: p1.ID}, Task: &harvest.Task{ID: dev.ID}, Hours: 1})
+	assert.True(harvest.IsValidationError(hv.DeleteTaskAssignment(ctx, p1.ID, a.ID)))
+
+	assert.NoError(hv.DeleteTaskAssignment(ctx, p1.ID, b.ID))
+	assert.Nil(srv.TaskAssignment(b.ID))
+}
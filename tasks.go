@@ -0,0 +1,83 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type Task struct {
+	// Unique ID for the task.
+	ID int64 `json:"id"`
+
+	// The name of the task.
+	Name string `json:"name"`
+
+	// Used in determining whether default tasks should be marked billable
+	// when creating a new project.
+	BillableByDefault bool `json:"billable_by_default,omitempty"`
+
+	// The hourly rate to use for this task when it is added to a project.
+	DefaultHourlyRate float64 `json:"default_hourly_rate,omitempty"`
+
+	// Whether this task should be automatically added to future projects.
+	IsDefault bool `json:"is_default,omitempty"`
+
+	// Whether this task is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Date and time the task was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the task was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateTask describes a new task, only Name is required.
+type CreateTask struct {
+	Name              string  `json:"name"`
+	BillableByDefault *bool   `json:"billable_by_default,omitempty"`
+	DefaultHourlyRate float64 `json:"default_hourly_rate,omitempty"`
+	IsDefault         bool    `json:"is_default,omitempty"`
+	IsActive          *bool   `json:"is_active,omitempty"`
+}
+
+// UpdateTask holds the fields to change on a task, nil and zero values are
+// left untouched.
+type UpdateTask struct {
+	Name              string   `json:"name,omitempty"`
+	BillableByDefault *bool    `json:"billable_by_default,omitempty"`
+	DefaultHourlyRate *float64 `json:"default_hourly_rate,omitempty"`
+	IsDefault         *bool    `json:"is_default,omitempty"`
+	IsActive          *bool    `json:"is_active,omitempty"`
+}
+
+func (hv *Client) Tasks(ctx context.Context, opts ...ActiveOption) iter.Seq2[*Task, error] {
+	return fetchIter[Task](ctx, hv, "tasks", "tasks", opts)
+}
+
+func (hv *Client) GetTask(ctx context.Context, id int64) (*Task, error) {
+	url := fmt.Sprintf("%s/tasks/%d", hv.baseURL, id)
+	return doJSON[Task](ctx, hv, "GET", url, nil, http.StatusOK, "load task")
+}
+
+func (hv *Client) CreateTask(ctx context.Context, t *CreateTask) (*Task, error) {
+	url := fmt.Sprintf("%s/tasks", hv.baseURL)
+	return doJSON[Task](ctx, hv, "POST", url, t, http.StatusCreated, "create task")
+}
+
+func (hv *Client) UpdateTask(ctx context.Context, id int64, t *UpdateTask) (*Task, error) {
+	url := fmt.Sprintf("%s/tasks/%d", hv.baseURL, id)
+	return doJSON[Task](ctx, hv, "PATCH", url, t, http.StatusOK, "update task")
+}
+
+// DeleteTask deletes a task. This is only possible as long as no time has been
+// tracked on the task in any project.
+func (hv *Client) DeleteTask(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/tasks/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete task")
+}
@@ -0,0 +1,66 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTasks(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddTask(&harvest.Task{Name: "Design"})
+
+	task, err := hv.CreateTask(ctx, &harvest.CreateTask{
+		Name:              "Development",
+		DefaultHourlyRate: 100,
+		IsDefault:         true,
+	})
+	assert.NoError(err)
+	assert.Equal("Development", task.Name)
+	assert.Equal(100.0, task.DefaultHourlyRate)
+	assert.True(task.BillableByDefault)
+	assert.True(task.IsDefault)
+	assert.True(task.IsActive)
+	assert.NotNil(task.Hv)
+
+	_, err = hv.CreateTask(ctx, &harvest.CreateTask{Name: "Development"})
+	assert.True(harvest.IsValidationError(err))
+
+	inactive := false
+	task, err = hv.UpdateTask(ctx, task.ID, &harvest.UpdateTask{IsActive: &inactive})
+	assert.NoError(err)
+	assert.False(task.IsActive)
+	assert.Equal(100.0, task.DefaultHourlyRate)
+
+	task, err = hv.GetTask(ctx, task.ID)
+	assert.NoError(err)
+	assert.False(task.IsActive)
+
+	names := []string{}
+	for t, err := range hv.Tasks(ctx) {
+		assert.NoError(err)
+		names = append(names, t.Name)
+	}
+	assert.Equal([]string{"Development", "Design"}, names)
+
+	// Can't delete tasks with tracked time
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: 1}, Task: &harvest.Task{ID: task.ID}, Hours: 1})
+	assert.True(harvest.IsValidationError(hv.DeleteTask(ctx, task.ID)))
+	assert.NotNil(srv.Task(task.ID))
+
+	var design *harvest.Task
+	for t, err := range hv.Tasks(ctx) {
+		assert.NoError(err)
+		if t.Name == "Design" {
+			design = t
+		}
+	}
+	assert.NoError(hv.DeleteTask(ctx, design.ID))
+	_, err = hv.GetTask(ctx, design.ID)
+	assert.True(harvest.IsNotFound(err))
+}
@@ -0,0 +1,184 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type TimeEntry struct {
+	// Unique ID for the time entry.
+	ID int64 `json:"id"`
+
+	// Date of the time entry.
+	SpentDate string `json:"spent_date"`
+
+	// An object containing the id and name of the associated user.
+	User *UserRef `json:"user"`
+
+	// An object containing the id and name of the associated client.
+	Customer *Customer `json:"client"`
+
+	// An object containing the id, name, and code of the associated project.
+	Project *Project `json:"project"`
+
+	// An object containing the id and name of the associated task.
+	Task *Task `json:"task"`
+
+	// Once the time entry has been invoiced, this field will include the
+	// associated invoice’s id and number.
+	Invoice *InvoiceRef `json:"invoice"`
+
+	// Number of (decimal time) hours tracked in this time entry.
+	Hours float64 `json:"hours"`
+
+	// Number of (decimal time) hours already tracked in this time entry,
+	// before the timer was last started.
+	HoursWithoutTimer float64 `json:"hours_without_timer"`
+
+	// Number of (decimal time) hours tracked in this time entry used in
+	// summary reports and invoices.
+	RoundedHours float64 `json:"rounded_hours"`
+
+	// Notes attached to the time entry.
+	Notes string `json:"notes"`
+
+	// Whether or not the time entry has been locked.
+	IsLocked bool `json:"is_locked"`
+
+	// Why the time entry has been locked.
+	LockedReason string `json:"locked_reason"`
+
+	// Whether or not the time entry has been approved via Timesheet Approval.
+	IsClosed bool `json:"is_closed"`
+
+	// Whether or not the time entry has been marked as invoiced.
+	IsBilled bool `json:"is_billed"`
+
+	// Date and time the running timer was started (if tracking by duration).
+	TimerStartedAt time.Time `json:"timer_started_at"`
+
+	// Time the time entry was started (if tracking by start/end times).
+	StartedTime string `json:"started_time"`
+
+	// Time the time entry was ended (if tracking by start/end times).
+	EndedTime string `json:"ended_time"`
+
+	// Whether or not the time entry is currently running.
+	IsRunning bool `json:"is_running"`
+
+	// Whether or not the time entry is billable.
+	Billable bool `json:"billable"`
+
+	// Whether or not the time entry counts towards the project budget.
+	Budgeted bool `json:"budgeted"`
+
+	// The billable rate for the time entry.
+	BillableRate float64 `json:"billable_rate"`
+
+	// The cost rate for the time entry.
+	CostRate float64 `json:"cost_rate"`
+
+	// Date and time the time entry was created.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Date and time the time entry was last updated.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Hv *Client `json:"-"`
+}
+
+type UserRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type InvoiceRef struct {
+	ID     int64  `json:"id"`
+	Number string `json:"number"`
+}
+
+// CreateTimeEntry describes a new time entry.
+//
+// Accounts that track time via duration should set Hours (leaving it empty
+// starts a running timer), accounts that track via start and end time should
+// set StartedTime and EndedTime (e.g. "8:00am") instead.
+type CreateTimeEntry struct {
+	UserID            int64              `json:"user_id,omitempty"`
+	ProjectID         int64              `json:"project_id"`
+	TaskID            int64              `json:"task_id"`
+	SpentDate         string             `json:"spent_date"`
+	Hours             float64            `json:"hours,omitempty"`
+	StartedTime       string             `json:"started_time,omitempty"`
+	EndedTime         string             `json:"ended_time,omitempty"`
+	Notes             string             `json:"notes,omitempty"`
+	ExternalReference *ExternalReference `json:"external_reference,omitempty"`
+}
+
+// UpdateTimeEntry holds the fields to change on a time entry, nil and zero
+// values are left untouched.
+type UpdateTimeEntry struct {
+	ProjectID         int64              `json:"project_id,omitempty"`
+	TaskID            int64              `json:"task_id,omitempty"`
+	SpentDate         string             `json:"spent_date,omitempty"`
+	Hours             *float64           `json:"hours,omitempty"`
+	StartedTime       *string            `json:"started_time,omitempty"`
+	EndedTime         *string            `json:"ended_time,omitempty"`
+	Notes             *string            `json:"notes,omitempty"`
+	ExternalReference *ExternalReference `json:"external_reference,omitempty"`
+}
+
+// ExternalReference links a time entry to an item in another application.
+type ExternalReference struct {
+	ID        string `json:"id"`
+	GroupID   string `json:"group_id"`
+	AccountID string `json:"account_id,omitempty"`
+	Permalink string `json:"permalink"`
+}
+
+func (hv *Client) TimeEntries(ctx context.Context, opts ...TimeEntryOption) iter.Seq2[*TimeEntry, error] {
+	return fetchIter[TimeEntry](ctx, hv, "time_entries", "time_entries", opts)
+}
+
+func (hv *Client) GetTimeEntry(ctx context.Context, id int64) (*TimeEntry, error) {
+	url := fmt.Sprintf("%s/time_entries/%d", hv.baseURL, id)
+	return doJSON[TimeEntry](ctx, hv, "GET", url, nil, http.StatusOK, "load time entry")
+}
+
+func (hv *Client) CreateTimeEntry(ctx context.Context, e *CreateTimeEntry) (*TimeEntry, error) {
+	url := fmt.Sprintf("%s/time_entries", hv.baseURL)
+	return doJSON[TimeEntry](ctx, hv, "POST", url, e, http.StatusCreated, "create time entry")
+}
+
+func (hv *Client) UpdateTimeEntry(ctx context.Context, id int64, e *UpdateTimeEntry) (*TimeEntry, error) {
+	url := fmt.Sprintf("%s/time_entries/%d", hv.baseURL, id)
+	return doJSON[TimeEntry](ctx, hv, "PATCH", url, e, http.StatusOK, "update time entry")
+}
+
+func (hv *Client) DeleteTimeEntry(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/time_entries/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete time entry")
+}
+
+// Restart restarts a stopped timer and updates t with the result.
+func (t *TimeEntry) Restart(ctx context.Context) error {
+	return t.timer(ctx, "restart")
+}
+
+// Stop stops a running timer and updates t with the result.
+func (t *TimeEntry) Stop(ctx context.Context) error {
+	return t.timer(ctx, "stop")
+}
+
+func (t *TimeEntry) timer(ctx context.Context, action string) error {
+	url := fmt.Sprintf("%s/time_entries/%d/%s", t.Hv.baseURL, t.ID, action)
+	r, err := doJSON[TimeEntry](ctx, t.Hv, "PATCH", url, nil, http.StatusOK, action+" time entry")
+	if err != nil {
+		return err
+	}
+
+	*t = *r
+	return nil
+}
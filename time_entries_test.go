@@ -0,0 +1,67 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeEntries(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddTimeEntry(&harvest.TimeEntry{SpentDate: "2024-01-01", Hours: 1.5, Notes: "Old"})
+
+	e, err := hv.CreateTimeEntry(ctx, &harvest.CreateTimeEntry{
+		ProjectID: 1,
+		TaskID:    2,
+		SpentDate: "2024-01-02",
+		Hours:     2,
+		Notes:     "Work",
+	})
+	assert.NoError(err)
+	assert.Equal(2.0, e.Hours)
+	assert.Equal(int64(1), e.Project.ID)
+	assert.Equal(int64(2), e.Task.ID)
+	assert.False(e.IsRunning)
+	assert.NotNil(e.Hv)
+
+	timer, err := hv.CreateTimeEntry(ctx, &harvest.CreateTimeEntry{ProjectID: 1, TaskID: 2, SpentDate: "2024-01-03"})
+	assert.NoError(err)
+	assert.True(timer.IsRunning)
+	assert.NoError(timer.Stop(ctx))
+	assert.False(timer.IsRunning)
+	assert.Error(timer.Stop(ctx))
+	assert.NoError(timer.Restart(ctx))
+	assert.True(timer.IsRunning)
+
+	notes := "More work"
+	e, err = hv.UpdateTimeEntry(ctx, e.ID, &harvest.UpdateTimeEntry{Notes: &notes})
+	assert.NoError(err)
+	assert.Equal("More work", e.Notes)
+
+	e, err = hv.GetTimeEntry(ctx, e.ID)
+	assert.NoError(err)
+	assert.Equal("More work", e.Notes)
+
+	// Notes can be cleared and hours set to zero
+	notes, hours := "", 0.0
+	e, err = hv.UpdateTimeEntry(ctx, e.ID, &harvest.UpdateTimeEntry{Notes: &notes, Hours: &hours})
+	assert.NoError(err)
+	assert.Equal("", e.Notes)
+	assert.Equal(0.0, e.Hours)
+
+	count := 0
+	for _, err := range hv.TimeEntries(ctx) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(3, count)
+
+	assert.NoError(hv.DeleteTimeEntry(ctx, e.ID))
+	_, err = hv.GetTimeEntry(ctx, e.ID)
+	assert.True(harvest.IsNotFound(err))
+}
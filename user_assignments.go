@@ -0,0 +1,151 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// UserAssignment gives a user access to a project.
+type UserAssignment struct {
+	// Unique ID for the user assignment.
+	ID int64 `json:"id"`
+
+	// An object containing the id, name, and code of the associated project.
+	Project *Project `json:"project,omitempty"`
+
+	// An object containing the id and name of the associated user.
+	User *UserRef `json:"user,omitempty"`
+
+	// Whether the user assignment is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Determines if the user has Project Manager permissions for the
+	// project.
+	IsProjectManager bool `json:"is_project_manager,omitempty"`
+
+	// Determines which billable rate(s) will be used on the project for this
+	// user when bill_by is People. When true, the project will use the
+	// user’s default billable rates. When false, the project will use the
+	// custom rate defined on this user assignment.
+	UseDefaultRates bool `json:"use_default_rates,omitempty"`
+
+	// Custom rate used when the project’s bill_by is People and
+	// use_default_rates is false.
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+
+	// Budget used when the project’s budget_by is person.
+	Budget float64 `json:"budget,omitempty"`
+
+	// Date and time the user assignment was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the user assignment was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// ProjectAssignment is a project a user is assigned to, as seen from the
+// user, including the tasks they can track time on.
+type ProjectAssignment struct {
+	// Unique ID for the user assignment.
+	ID int64 `json:"id"`
+
+	// An object containing the id, name, and code of the assigned project.
+	Project *Project `json:"project,omitempty"`
+
+	// An object containing the id and name of the client of the project.
+	Customer *Customer `json:"client,omitempty"`
+
+	// The task assignments of the project.
+	TaskAssignments []*TaskAssignment `json:"task_assignments,omitempty"`
+
+	// Whether the assignment is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// Determines if the user has Project Manager permissions for the
+	// project.
+	IsProjectManager bool `json:"is_project_manager,omitempty"`
+
+	// Whether the project uses the user’s default billable rates.
+	UseDefaultRates bool `json:"use_default_rates,omitempty"`
+
+	// Custom rate used when the project’s bill_by is People and
+	// use_default_rates is false.
+	HourlyRate float64 `json:"hourly_rate,omitempty"`
+
+	// Budget used when the project’s budget_by is person.
+	Budget float64 `json:"budget,omitempty"`
+
+	// Date and time the assignment was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the assignment was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// CreateUserAssignment describes a new user assignment, only UserID is
+// required.
+type CreateUserAssignment struct {
+	UserID           int64   `json:"user_id"`
+	IsActive         *bool   `json:"is_active,omitempty"`
+	IsProjectManager bool    `json:"is_project_manager,omitempty"`
+	UseDefaultRates  *bool   `json:"use_default_rates,omitempty"`
+	HourlyRate       float64 `json:"hourly_rate,omitempty"`
+	Budget           float64 `json:"budget,omitempty"`
+}
+
+// UpdateUserAssignment holds the fields to change on a user assignment, nil
+// values are left untouched.
+type UpdateUserAssignment struct {
+	IsActive         *bool    `json:"is_active,omitempty"`
+	IsProjectManager *bool    `json:"is_project_manager,omitempty"`
+	UseDefaultRates  *bool    `json:"use_default_rates,omitempty"`
+	HourlyRate       *float64 `json:"hourly_rate,omitempty"`
+	Budget           *float64 `json:"budget,omitempty"`
+}
+
+// UserAssignments lists the user assignments of all projects.
+func (hv *Client) UserAssignments(ctx context.Context, opts ...UserAssignmentOption) iter.Seq2[*UserAssignment, error] {
+	return fetchIter[UserAssignment](ctx, hv, "user_assignments", "user_assignments", opts)
+}
+
+// ProjectUserAssignments lists the user assignments of a project.
+func (hv *Client) ProjectUserAssignments(ctx context.Context, projectID int64, opts ...UserAssignmentOption) iter.Seq2[*UserAssignment, error] {
+	return fetchIter[UserAssignment](ctx, hv, "user_assignments", fmt.Sprintf("projects/%d/user_assignments", projectID), opts)
+}
+
+func (hv *Client) GetUserAssignment(ctx context.Context, projectID, id int64) (*UserAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/user_assignments/%d", hv.baseURL, projectID, id)
+	return doJSON[UserAssignment](ctx, hv, "GET", url, nil, http.StatusOK, "load user assignment")
+}
+
+func (hv *Client) CreateUserAssignment(ctx context.Context, projectID int64, a *CreateUserAssignment) (*UserAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/user_assignments", hv.baseURL, projectID)
+	return doJSON[UserAssignment](ctx, hv, "POST", url, a, http.StatusCreated, "create user assignment")
+}
+
+func (hv *Client) UpdateUserAssignment(ctx context.Context, projectID, id int64, a *UpdateUserAssignment) (*UserAssignment, error) {
+	url := fmt.Sprintf("%s/projects/%d/user_assignments/%d", hv.baseURL, projectID, id)
+	return doJSON[UserAssignment](ctx, hv, "PATCH", url, a, http.StatusOK, "update user assignment")
+}
+
+// DeleteUserAssignment removes a user from a project. Assignments with
+// tracked time can't be deleted, archive them instead.
+func (hv *Client) DeleteUserAssignment(ctx context.Context, projectID, id int64) error {
+	url := fmt.Sprintf("%s/projects/%d/user_assignments/%d", hv.baseURL, projectID, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete user assignment")
+}
+
+// ProjectAssignments lists the projects a user is assigned to.
+func (hv *Client) ProjectAssignments(ctx context.Context, userID int64, opts ...ListOption) iter.Seq2[*ProjectAssignment, error] {
+	return fetchIter[ProjectAssignment](ctx, hv, "project_assignments", fmt.Sprintf("users/%d/project_assignments", userID), opts)
+}
+
+// MyProjectAssignments lists the projects the current user is assigned to.
+func (hv *Client) MyProjectAssignments(ctx context.Context, opts ...ListOption) iter.Seq2[*ProjectAssignment, error] {
+	return fetchIter[ProjectAssignment](ctx, hv, "project_assignments", "users/me/project_assignments", opts)
+}
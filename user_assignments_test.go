@@ -0,0 +1,97 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAssignments(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	me := &harvest.User{FirstName: "Jane", LastName: "Admin", Email: "jane@example.com"}
+	srv.AddUser(me)
+	john := &harvest.User{FirstName: "John", LastName: "Doe", Email: "john@example.com", DefaultHourlyRate: 90}
+	srv.AddUser(john)
+	acme := &harvest.Customer{Name: "ACME"}
+	srv.AddCustomer(acme)
+	p1 := &harvest.Project{Customer: &harvest.Customer{ID: acme.ID}, Name: "Website", Code: "WEB"}
+	srv.AddProject(p1)
+	p2 := &harvest.Project{Customer: &harvest.Customer{ID: acme.ID}, Name: "App"}
+	srv.AddProject(p2)
+	dev := &harvest.Task{Name: "Development"}
+	srv.AddTask(dev)
+	srv.AddTaskAssignment(p1.ID, &harvest.TaskAssignment{Task: &harvest.Task{ID: dev.ID}})
+	srv.AddUserAssignment(p2.ID, &harvest.UserAssignment{User: &harvest.UserRef{ID: me.ID}})
+
+	a, err := hv.CreateUserAssignment(ctx, p1.ID, &harvest.CreateUserAssignment{UserID: john.ID, IsProjectManager: true})
+	assert.NoError(err)
+	assert.Equal(p1.ID, a.Project.ID)
+	assert.Equal("John Doe", a.User.Name)
+	assert.True(a.IsProjectManager)
+	assert.True(a.UseDefaultRates)
+	assert.Equal(90.0, a.HourlyRate)
+
+	_, err = hv.CreateUserAssignment(ctx, p1.ID, &harvest.CreateUserAssignment{UserID: john.ID})
+	assert.True(harvest.IsValidationError(err))
+
+	b, err := hv.CreateUserAssignment(ctx, p1.ID, &harvest.CreateUserAssignment{UserID: me.ID})
+	assert.NoError(err)
+
+	custom := false
+	rate := 110.0
+	a, err = hv.UpdateUserAssignment(ctx, p1.ID, a.ID, &harvest.UpdateUserAssignment{UseDefaultRates: &custom, HourlyRate: &rate})
+	assert.NoError(err)
+	assert.False(a.UseDefaultRates)
+	assert.Equal(110.0, a.HourlyRate)
+	assert.True(a.IsProjectManager)
+
+	a, err = hv.GetUserAssignment(ctx, p1.ID, a.ID)
+	assert.NoError(err)
+	assert.Equal(110.0, a.HourlyRate)
+
+	users := []string{}
+	for a, err := range hv.ProjectUserAssignments(ctx, p1.ID) {
+		assert.NoError(err)
+		users = append(users, a.User.Name)
+	}
+	assert.Equal([]string{"Jane Admin", "John Doe"}, users)
+
+	count := 0
+	for _, err := range hv.UserAssignments(ctx) {
+		assert.NoError(err)
+		count++
+	}
+	assert.Equal(3, count)
+
+	// The projects of the current user, including their tasks
+	projects := []string{}
+	for p, err := range hv.MyProjectAssignments(ctx) {
+		assert.NoError(err)
+		assert.Equal("ACME", p.Customer.Name)
+		projects = append(projects, p.Project.Name)
+		if p.Project.ID == p1.ID && assert.Len(p.TaskAssignments, 1) {
+			assert.Equal("Development", p.TaskAssignments[0].Task.Name)
+		}
+	}
+	assert.Equal([]string{"Website", "App"}, projects)
+
+	count = 0
+	for p, err := range hv.ProjectAssignments(ctx, john.ID) {
+		assert.NoError(err)
+		assert.Equal(p1.ID, p.Project.ID)
+		count++
+	}
+	assert.Equal(1, count)
+
+	// Can't delete assignments with tracked time
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: p1.ID}, User: &harvest.UserRef{ID: john.ID}, Hours: 1})
+	assert.True(harvest.IsValidationError(hv.DeleteUserAssignment(ctx, p1.ID, a.ID)))
+
+	assert.NoError(hv.DeleteUserAssignment(ctx, p1.ID, b.ID))
+	assert.Nil(srv.UserAssignment(b.ID))
+}
@@ -0,0 +1,202 @@
+package harvest
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type User struct {
+	// Unique ID for the user.
+	ID int64 `json:"id"`
+
+	// The first name of the user.
+	FirstName string `json:"first_name"`
+
+	// The last name of the user.
+	LastName string `json:"last_name"`
+
+	// The email address of the user.
+	Email string `json:"email"`
+
+	// The user’s telephone number.
+	Telephone string `json:"telephone,omitempty"`
+
+	// The user’s timezone.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Whether the user should be automatically added to future projects.
+	HasAccessToAllFutureProjects bool `json:"has_access_to_all_future_projects,omitempty"`
+
+	// Whether the user is a contractor or an employee.
+	IsContractor bool `json:"is_contractor,omitempty"`
+
+	// Whether the user is active or archived.
+	IsActive bool `json:"is_active,omitempty"`
+
+	// The number of hours per week this person is available to work in
+	// seconds.
+	WeeklyCapacity int64 `json:"weekly_capacity,omitempty"`
+
+	// The billable rate to use for this user when they are added to a
+	// project.
+	DefaultHourlyRate float64 `json:"default_hourly_rate,omitempty"`
+
+	// The cost rate to use for this user when calculating a project’s costs
+	// vs billable amount.
+	CostRate float64 `json:"cost_rate,omitempty"`
+
+	// Descriptive names of the business roles assigned to this person.
+	Roles []string `json:"roles,omitempty"`
+
+	// Access role(s) that determine the user’s permissions in Harvest:
+	// administrator, manager or member, with additional permissions for
+	// managers.
+	AccessRoles []string `json:"access_roles,omitempty"`
+
+	// The URL to the user’s avatar image.
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	// Date and time the user was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the user was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	Hv *Client `json:"-"`
+}
+
+// CreateUser describes a new user. FirstName, LastName and Email are
+// required.
+type CreateUser struct {
+	FirstName                    string   `json:"first_name"`
+	LastName                     string   `json:"last_name"`
+	Email                        string   `json:"email"`
+	Timezone                     string   `json:"timezone,omitempty"`
+	HasAccessToAllFutureProjects bool     `json:"has_access_to_all_future_projects,omitempty"`
+	IsContractor                 bool     `json:"is_contractor,omitempty"`
+	IsActive                     *bool    `json:"is_active,omitempty"`
+	WeeklyCapacity               int64    `json:"weekly_capacity,omitempty"`
+	DefaultHourlyRate            float64  `json:"default_hourly_rate,omitempty"`
+	CostRate                     float64  `json:"cost_rate,omitempty"`
+	Roles                        []string `json:"roles,omitempty"`
+	AccessRoles                  []string `json:"access_roles,omitempty"`
+}
+
+// UpdateUser holds the fields to change on a user, nil and zero values are
+// left untouched.
+type UpdateUser struct {
+	FirstName                    string   `json:"first_name,omitempty"`
+	LastName                     string   `json:"last_name,omitempty"`
+	Email                        string   `json:"email,omitempty"`
+	Timezone                     string   `json:"timezone,omitempty"`
+	HasAccessToAllFutureProjects *bool    `json:"has_access_to_all_future_projects,omitempty"`
+	IsContractor                 *bool    `json:"is_contractor,omitempty"`
+	IsActive                     *bool    `json:"is_active,omitempty"`
+	WeeklyCapacity               *int64   `json:"weekly_capacity,omitempty"`
+	Roles                        []string `json:"roles,omitempty"`
+	AccessRoles                  []string `json:"access_roles,omitempty"`
+}
+
+// Rate is a billable or cost rate of a user, valid from StartDate until
+// EndDate.
+type Rate struct {
+	// Unique ID for the rate.
+	ID int64 `json:"id"`
+
+	// The amount of the rate.
+	Amount float64 `json:"amount"`
+
+	// The date the rate takes effect, empty for the initial rate.
+	StartDate string `json:"start_date,omitempty"`
+
+	// The date the rate is no longer in effect, empty for the current rate.
+	EndDate string `json:"end_date,omitempty"`
+
+	// Date and time the rate was created.
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	// Date and time the rate was last updated.
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// CreateRate describes a new rate, only Amount is required. Leaving
+// StartDate empty makes it the initial rate.
+type CreateRate struct {
+	Amount    float64 `json:"amount"`
+	StartDate string  `json:"start_date,omitempty"`
+}
+
+// Name returns the full name of the user.
+func (u *User) Name() string {
+	return strings.TrimSpace(u.FirstName + " " + u.LastName)
+}
+
+func (hv *Client) Users(ctx context.Context, opts ...ActiveOption) iter.Seq2[*User, error] {
+	return fetchIter[User](ctx, hv, "users", "users", opts)
+}
+
+func (hv *Client) GetUser(ctx context.Context, id int64) (*User, error) {
+	url := fmt.Sprintf("%s/users/%d", hv.baseURL, id)
+	return doJSON[User](ctx, hv, "GET", url, nil, http.StatusOK, "load user")
+}
+
+// Me returns the user the access token belongs to.
+func (hv *Client) Me(ctx context.Context) (*User, error) {
+	url := fmt.Sprintf("%s/users/me", hv.baseURL)
+	return doJSON[User](ctx, hv, "GET", url, nil, http.StatusOK, "load current user")
+}
+
+func (hv *Client) CreateUser(ctx context.Context, u *CreateUser) (*User, error) {
+	url := fmt.Sprintf("%s/users", hv.baseURL)
+	return doJSON[User](ctx, hv, "POST", url, u, http.StatusCreated, "create user")
+}
+
+func (hv *Client) UpdateUser(ctx context.Context, id int64, u *UpdateUser) (*User, error) {
+	url := fmt.Sprintf("%s/users/%d", hv.baseURL, id)
+	return doJSON[User](ctx, hv, "PATCH", url, u, http.StatusOK, "update user")
+}
+
+// DeleteUser deletes a user. Once a user has tracked time or logged expenses,
+// Harvest returns a validation error and the user can only be deactivated.
+func (hv *Client) DeleteUser(ctx context.Context, id int64) error {
+	url := fmt.Sprintf("%s/users/%d", hv.baseURL, id)
+	return hv.call(ctx, "DELETE", url, nil, http.StatusOK, "delete user")
+}
+
+// BillableRates lists the billable rates of a user, oldest first.
+func (hv *Client) BillableRates(ctx context.Context, userID int64, opts ...PageOption) iter.Seq2[*Rate, error] {
+	return fetchIter[Rate](ctx, hv, "billable_rates", fmt.Sprintf("users/%d/billable_rates", userID), opts)
+}
+
+func (hv *Client) GetBillableRate(ctx context.Context, userID, id int64) (*Rate, error) {
+	url := fmt.Sprintf("%s/users/%d/billable_rates/%d", hv.baseURL, userID, id)
+	return doJSON[Rate](ctx, hv, "GET", url, nil, http.StatusOK, "load billable rate")
+}
+
+// CreateBillableRate adds a billable rate for a user. The previous rate ends
+// the day before it starts.
+func (hv *Client) CreateBillableRate(ctx context.Context, userID int64, r *CreateRate) (*Rate, error) {
+	url := fmt.Sprintf("%s/users/%d/billable_rates", hv.baseURL, userID)
+	return doJSON[Rate](ctx, hv, "POST", url, r, http.StatusCreated, "create billable rate")
+}
+
+// CostRates lists the cost rates of a user, oldest first.
+func (hv *Client) CostRates(ctx context.Context, userID int64, opts ...PageOption) iter.Seq2[*Rate, error] {
+	return fetchIter[Rate](ctx, hv, "cost_rates", fmt.Sprintf("users/%d/cost_rates", userID), opts)
+}
+
+func (hv *Client) GetCostRate(ctx context.Context, userID, id int64) (*Rate, error) {
+	url := fmt.Sprintf("%s/users/%d/cost_rates/%d", hv.baseURL, userID, id)
+	return doJSON[Rate](ctx, hv, "GET", url, nil, http.StatusOK, "load cost rate")
+}
+
+// CreateCostRate adds a cost rate for a user. The previous rate ends the day
+// before it starts.
+func (hv *Client) CreateCostRate(ctx context.Context, userID int64, r *CreateRate) (*Rate, error) {
+	url := fmt.Sprintf("%s/users/%d/cost_rates", hv.baseURL, userID)
+	return doJSON[Rate](ctx, hv, "POST", url, r, http.StatusCreated, "create cost rate")
+}
@@ -0,0 +1,122 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rubenv/harvest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsers(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	srv.AddUser(&harvest.User{FirstName: "Jane", LastName: "Admin", Email: "jane@example.com", AccessRoles: []string{"administrator"}})
+
+	me, err := hv.Me(ctx)
+	assert.NoError(err)
+	assert.Equal("Jane Admin", me.Name())
+	assert.Equal([]string{"administrator"}, me.AccessRoles)
+
+	u, err := hv.CreateUser(ctx, &harvest.CreateUser{
+		FirstName:         "John",
+		LastName:          "Doe",
+		Email:             "john@example.com",
+		IsContractor:      true,
+		DefaultHourlyRate: 80,
+		Roles:             []string{"Developer"},
+	})
+	assert.NoError(err)
+	assert.Equal("John Doe", u.Name())
+	assert.True(u.IsContractor)
+	assert.True(u.IsActive)
+	assert.Equal(80.0, u.DefaultHourlyRate)
+	assert.Equal([]string{"Developer"}, u.Roles)
+	assert.Equal([]string{"member"}, u.AccessRoles)
+	assert.NotNil(u.Hv)
+
+	_, err = hv.CreateUser(ctx, &harvest.CreateUser{FirstName: "John", LastName: "Again", Email: "john@example.com"})
+	assert.True(harvest.IsValidationError(err))
+	_, err = hv.CreateUser(ctx, &harvest.CreateUser{FirstName: "John"})
+	assert.True(harvest.IsValidationError(err))
+
+	capacity := int64(72000)
+	u, err = hv.UpdateUser(ctx, u.ID, &harvest.UpdateUser{WeeklyCapacity: &capacity})
+	assert.NoError(err)
+	assert.Equal(int64(72000), u.WeeklyCapacity)
+	assert.Equal("john@example.com", u.Email)
+
+	u, err = hv.GetUser(ctx, u.ID)
+	assert.NoError(err)
+	assert.Equal(int64(72000), u.WeeklyCapacity)
+
+	emails := []string{}
+	for u, err := range hv.Users(ctx) {
+		assert.NoError(err)
+		emails = append(emails, u.Email)
+	}
+	assert.Equal([]string{"john@example.com", "jane@example.com"}, emails)
+
+	// Can't delete users with tracked time
+	srv.AddTimeEntry(&harvest.TimeEntry{Project: &harvest.Project{ID: 1}, User: &harvest.UserRef{ID: me.ID}, Hours: 1})
+	assert.True(harvest.IsValidationError(hv.DeleteUser(ctx, me.ID)))
+
+	assert.NoError(hv.DeleteUser(ctx, u.ID))
+	_, err = hv.GetUser(ctx, u.ID)
+	assert.True(harvest.IsNotFound(err))
+}
+
+func TestUserRates(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	u := &harvest.User{FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+	srv.AddUser(u)
+
+	r, err := hv.CreateBillableRate(ctx, u.ID, &harvest.CreateRate{Amount: 100})
+	assert.NoError(err)
+	assert.Equal(100.0, r.Amount)
+	assert.Empty(r.StartDate)
+
+	start := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	_, err = hv.CreateBillableRate(ctx, u.ID, &harvest.CreateRate{Amount: 120, StartDate: start})
+	assert.NoError(err)
+
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	_, err = hv.CreateBillableRate(ctx, u.ID, &harvest.CreateRate{Amount: 150, StartDate: future})
+	assert.True(harvest.IsValidationError(err))
+
+	rates := []*harvest.Rate{}
+	for r, err := range hv.BillableRates(ctx, u.ID) {
+		assert.NoError(err)
+		rates = append(rates, r)
+	}
+	if assert.Len(rates, 2) {
+		assert.Equal(100.0, rates[0].Amount)
+		assert.Equal(time.Now().AddDate(0, 0, -2).Format("2006-01-02"), rates[0].EndDate)
+		assert.Equal(120.0, rates[1].Amount)
+		assert.Empty(rates[1].EndDate)
+	}
+
+	r, err = hv.GetBillableRate(ctx, u.ID, r.ID)
+	assert.NoError(err)
+	assert.Equal(100.0, r.Amount)
+
+	_, err = hv.CreateCostRate(ctx, u.ID, &harvest.CreateRate{Amount: 60})
+	assert.NoError(err)
+	count := 0
+	for r, err := range hv.CostRates(ctx, u.ID) {
+		assert.NoError(err)
+		assert.Equal(60.0, r.Amount)
+		count++
+	}
+	assert.Equal(1, count)
+
+	user := srv.User(u.ID)
+	assert.Equal(120.0, user.DefaultHourlyRate)
+	assert.Equal(60.0, user.CostRate)
+}
@@ -0,0 +1,311 @@
+package harvest
+
+import (
+	"context"
+	"iter"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultWatchInterval = time.Minute
+
+// How far back the first poll of a watcher without Since looks, to find the
+// latest change according to the Harvest clock. Covers the difference
+// between the local clock and that of Harvest.
+const watchClockSkew = 15 * time.Minute
+
+// EventType tells whether a watched object was created or updated.
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+)
+
+// Event is a change to an object, found by a Watcher.
+type Event[T any] struct {
+	Type   EventType
+	Object *T
+}
+
+// Watcher polls Harvest for objects that have been created or updated, using
+// the updated_since filter. It keeps track of the last change it has seen
+// (the mark), so each change is reported once.
+//
+// Deleted objects aren't reported, Harvest has no way to list them. Since
+// Harvest timestamps have a one second resolution, a second change within the
+// same second as the mark might be missed.
+type Watcher[T any] struct {
+	// Time between polls.
+	Interval time.Duration
+
+	// Changes before Since are ignored. Set it to resume from a previous
+	// Mark. When left zero, the first poll doesn't report anything but sets
+	// the mark to the latest change Harvest knows of, so later polls report
+	// what changed after it. This goes by the timestamps of Harvest rather
+	// than the local clock, which might be ahead.
+	Since time.Time
+
+	// Lists the objects changed since the given time.
+	list func(ctx context.Context, since time.Time) iter.Seq2[*T, error]
+
+	// Returns the ID and timestamps of an object.
+	stamps func(obj *T) (int64, time.Time, time.Time)
+
+	mu   sync.Mutex
+	mark time.Time
+
+	// IDs of the objects last updated at mark.
+	seen map[int64]bool
+}
+
+// stamped is implemented by the objects a Watcher can watch.
+type stamped interface {
+	stamps() (id int64, created, updated time.Time)
+}
+
+func newWatcher[T any, PT interface {
+	*T
+	stamped
+}](list func(ctx context.Context, since time.Time) iter.Seq2[*T, error]) *Watcher[T] {
+	return &Watcher[T]{
+		Interval: defaultWatchInterval,
+		list:     list,
+		stamps: func(obj *T) (int64, time.Time, time.Time) {
+			return PT(obj).stamps()
+		},
+	}
+}
+
+// WatchInvoices watches for new and updated invoices, opts can be used to
+// filter them (e.g. WithClientID).
+func (hv *Client) WatchInvoices(opts ...InvoiceOption) *Watcher[Invoice] {
+	return newWatcher(hv.changedInvoices(opts))
+}
+
+func (hv *Client) changedInvoices(opts []InvoiceOption) func(ctx context.Context, since time.Time) iter.Seq2[*Invoice, error] {
+	return func(ctx context.Context, since time.Time) iter.Seq2[*Invoice, error] {
+		return hv.Invoices(ctx, append([]InvoiceOption{WithUpdatedSince(since)}, opts...)...)
+	}
+}
+
+// WatchEstimates watches for new and updated estimates.
+func (hv *Client) WatchEstimates(opts ...EstimateOption) *Watcher[Estimate] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Estimate, error] {
+		return hv.Estimates(ctx, append([]EstimateOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchExpenses watches for new and updated expenses.
+func (hv *Client) WatchExpenses(opts ...ExpenseOption) *Watcher[Expense] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Expense, error] {
+		return hv.Expenses(ctx, append([]ExpenseOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchTimeEntries watches for new and updated time entries.
+func (hv *Client) WatchTimeEntries(opts ...TimeEntryOption) *Watcher[TimeEntry] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*TimeEntry, error] {
+		return hv.TimeEntries(ctx, append([]TimeEntryOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchProjects watches for new and updated projects.
+func (hv *Client) WatchProjects(opts ...ProjectOption) *Watcher[Project] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Project, error] {
+		return hv.Projects(ctx, append([]ProjectOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchCustomers watches for new and updated clients.
+func (hv *Client) WatchCustomers(opts ...ActiveOption) *Watcher[Customer] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Customer, error] {
+		return hv.Customers(ctx, append([]ActiveOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchContacts watches for new and updated contacts.
+func (hv *Client) WatchContacts(opts ...ContactOption) *Watcher[Contact] {
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Contact, error] {
+		return hv.Contacts(ctx, append([]ContactOption{WithUpdatedSince(since)}, opts...)...)
+	})
+}
+
+// WatchPayments watches for new and updated invoice payments. Harvest has no
+// listing of all payments, so this looks at the payments of the invoices that
+// changed, opts filter those invoices.
+func (hv *Client) WatchPayments(opts ...InvoiceOption) *Watcher[Payment] {
+	invoices := hv.changedInvoices(opts)
+	return newWatcher(func(ctx context.Context, since time.Time) iter.Seq2[*Payment, error] {
+		return func(yield func(*Payment, error) bool) {
+			for i, err := range invoices(ctx, since) {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+
+				// The invoice filters don't apply to payments.
+				for p, err := range i.Payments(ctx, WithUpdatedSince(since)) {
+					if !yield(p, err) || err != nil {
+						return
+					}
+				}
+			}
+		}
+	})
+}
+
+// Mark returns the time of the last change seen, which can be stored and
+// used as Since to resume watching later on.
+func (w *Watcher[T]) Mark() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.mark.IsZero() {
+		return w.Since
+	}
+	return w.mark
+}
+
+// Poll checks for changes once, returning them in the order they happened.
+// The mark only moves forward when the poll succeeds, so failed polls can be
+// retried.
+func (w *Watcher[T]) Poll(ctx context.Context) ([]*Event[T], error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	mark := w.mark
+	if mark.IsZero() {
+		mark = w.Since
+	}
+	if mark.IsZero() {
+		return nil, w.seed(ctx)
+	}
+
+	type change struct {
+		event   *Event[T]
+		id      int64
+		updated time.Time
+	}
+
+	changes := make([]change, 0)
+	for obj, err := range w.list(ctx, mark) {
+		if err != nil {
+			return nil, err
+		}
+
+		id, created, updated := w.stamps(obj)
+		if updated.Before(mark) || (updated.Equal(mark) && w.seen[id]) {
+			continue
+		}
+
+		event := &Event[T]{Type: EventUpdated, Object: obj}
+		if created.After(mark) || (created.Equal(mark) && !w.seen[id]) {
+			event.Type = EventCreated
+		}
+		changes = append(changes, change{event, id, updated})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].updated.Before(changes[j].updated)
+	})
+
+	events := make([]*Event[T], 0, len(changes))
+	for _, c := range changes {
+		events = append(events, c.event)
+
+		if c.updated.After(mark) {
+			mark = c.updated
+			w.seen = make(map[int64]bool)
+		}
+		if w.seen == nil {
+			w.seen = make(map[int64]bool)
+		}
+		w.seen[c.id] = true
+	}
+	w.mark = mark
+	return events, nil
+}
+
+// Events polls for changes every Interval, until ctx is done or the caller
+// stops iterating. Failed polls are yielded as errors, polling continues
+// afterwards.
+func (w *Watcher[T]) Events(ctx context.Context) iter.Seq2[*Event[T], error] {
+	return func(yield func(*Event[T], error) bool) {
+		for {
+			events, err := w.Poll(ctx)
+			if err != nil {
+				if ctx.Err() != nil || !yield(nil, err) {
+					return
+				}
+			}
+
+			for _, e := range events {
+				if !yield(e, nil) {
+					return
+				}
+			}
+
+			if sleep(ctx, w.Interval) != nil {
+				return
+			}
+		}
+	}
+}
+
+// Run polls for changes every Interval and sends them to ch, until ctx is
+// done or a poll fails.
+func (w *Watcher[T]) Run(ctx context.Context, ch chan<- *Event[T]) error {
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		err = sleep(ctx, w.Interval)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// seed sets the mark to the latest change, without reporting anything.
+func (w *Watcher[T]) seed(ctx context.Context) error {
+	mark := time.Now().Add(-watchClockSkew).Truncate(time.Second)
+	seen := make(map[int64]bool)
+	for obj, err := range w.list(ctx, mark) {
+		if err != nil {
+			return err
+		}
+
+		id, _, updated := w.stamps(obj)
+		if updated.After(mark) {
+			mark = updated
+			seen = make(map[int64]bool)
+		}
+		if updated.Equal(mark) {
+			seen[id] = true
+		}
+	}
+	w.mark = mark
+	w.seen = seen
+	return nil
+}
+
+func (i *Invoice) stamps() (int64, time.Time, time.Time)   { return i.ID, i.CreatedAt, i.UpdatedAt }
+func (e *Estimate) stamps() (int64, time.Time, time.Time)  { return e.ID, e.CreatedAt, e.UpdatedAt }
+func (e *Expense) stamps() (int64, time.Time, time.Time)   { return e.ID, e.CreatedAt, e.UpdatedAt }
+func (e *TimeEntry) stamps() (int64, time.Time, time.Time) { return e.ID, e.CreatedAt, e.UpdatedAt }
+func (p *Project) stamps() (int64, time.Time, time.Time)   { return p.ID, p.CreatedAt, p.UpdatedAt }
+func (c *Customer) stamps() (int64, time.Time, time.Time)  { return c.ID, c.CreatedAt, c.UpdatedAt }
+func (c *Contact) stamps() (int64, time.Time, time.Time)   { return c.ID, c.CreatedAt, c.UpdatedAt }
+func (p *Payment) stamps() (int64, time.Time, time.Time)   { return p.ID, p.CreatedAt, p.UpdatedAt }
@@ -0,0 +1,194 @@
+package harvest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rubenv/harvest"
+	"github.com/rubenv/harvest/harvesttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	hour := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	old := &harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Old", CreatedAt: hour.Add(-time.Hour), UpdatedAt: hour.Add(-time.Hour)}
+	srv.AddInvoice(old)
+	recent := &harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Recent", CreatedAt: hour, UpdatedAt: hour}
+	srv.AddInvoice(recent)
+
+	w := hv.WatchInvoices()
+	w.Since = hour.Add(-time.Minute)
+
+	events, err := w.Poll(ctx)
+	assert.NoError(err)
+	if assert.Len(events, 1) {
+		assert.Equal(harvest.EventCreated, events[0].Type)
+		assert.Equal("Recent", events[0].Object.Subject)
+	}
+	assert.Equal(hour, w.Mark().UTC())
+
+	// Nothing changed
+	events, err = w.Poll(ctx)
+	assert.NoError(err)
+	assert.Empty(events)
+
+	i, err := hv.GetInvoice(ctx, old.ID)
+	assert.NoError(err)
+	subject := "Updated"
+	assert.NoError(i.Update(ctx, &harvest.UpdateInvoice{Subject: &subject}))
+	_, err = hv.CreateInvoice(ctx, &harvest.Invoice{ClientID: 1, Subject: "New"})
+	assert.NoError(err)
+
+	events, err = w.Poll(ctx)
+	assert.NoError(err)
+	if assert.Len(events, 2) {
+		types := map[string]harvest.EventType{}
+		for _, e := range events {
+			types[e.Object.Subject] = e.Type
+		}
+		assert.Equal(harvest.EventUpdated, types["Updated"])
+		assert.Equal(harvest.EventCreated, types["New"])
+	}
+
+	// Failures don't move the mark
+	mark := w.Mark()
+	srv.Inject(harvesttest.Fault{Method: "GET", Path: "/invoices", Status: 500})
+	_, err = w.Poll(ctx)
+	assert.Error(err)
+	assert.Equal(mark, w.Mark())
+
+	// Resume from a stored mark
+	w2 := hv.WatchInvoices()
+	w2.Since = hour
+	events, err = w2.Poll(ctx)
+	assert.NoError(err)
+	assert.Len(events, 3)
+}
+
+func TestWatcherEvents(t *testing.T) {
+	assert := assert.New(t)
+	srv, hv := newFake(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	since := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	srv.AddExpense(&harvest.Expense{Project: &harvest.Project{ID: 1}, TotalCost: 10, CreatedAt: since, UpdatedAt: since})
+
+	w := hv.WatchExpenses()
+	w.Since = since
+	w.Interval = 10 * time.Millisecond
+
+	count := 0
+	for e, err := range w.Events(ctx) {
+		assert.NoError(err)
+		assert.Equal(harvest.EventCreated, e.Type)
+		count++
+		if count == 1 {
+			// Picked up on the next poll
+			_, err := hv.CreateExpense(ctx, &harvest.CreateExpense{ProjectID: 1, ExpenseCategoryID: 1, SpentDate: "2024-01-01", TotalCost: 5})
+			assert.NoError(err)
+		} else {
+			assert.Equal(5.0, e.Object.TotalCost)
+			break
+		}
+	}
+	assert.Equal(2, count)
+	assert.NoError(ctx.Err())
+}
+
+func TestWatchPayments(t *testing.T) {
+	assert := assert.New(t)
+	srv, hv := newFake(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	inv := &harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Amount: 100, State: "open"}
+	srv.AddInvoice(inv)
+
+	w := hv.WatchPayments()
+	w.Since = time.Now().Add(-time.Minute)
+	w.Interval = 10 * time.Millisecond
+
+	ch := make(chan *harvest.Event[harvest.Payment])
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx, ch)
+	}()
+
+	i, err := hv.GetInvoice(ctx, inv.ID)
+	assert.NoError(err)
+	_, err = i.CreatePayment(ctx, &harvest.CreatePayment{Amount: 100})
+	assert.NoError(err)
+
+	select {
+	case e := <-ch:
+		assert.Equal(harvest.EventCreated, e.Type)
+		assert.Equal(100.0, e.Object.Amount)
+	case <-ctx.Done():
+		t.Fatal("No payment event")
+	}
+
+	cancel()
+	assert.ErrorIs(<-done, context.Canceled)
+}
+
+func TestWatchPaymentsFilters(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	paid := &harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Amount: 100, State: "open"}
+	srv.AddInvoice(paid)
+	other := &harvest.Invoice{Customer: &harvest.Customer{ID: 2}, Amount: 50, State: "open"}
+	srv.AddInvoice(other)
+
+	w := hv.WatchPayments(harvest.WithClientID(1), harvest.WithInvoiceState(harvest.InvoicePaid))
+	w.Since = time.Now().Add(-time.Minute)
+
+	for _, inv := range []*harvest.Invoice{paid, other} {
+		i, err := hv.GetInvoice(ctx, inv.ID)
+		assert.NoError(err)
+		_, err = i.CreatePayment(ctx, &harvest.CreatePayment{Amount: i.Amount})
+		assert.NoError(err)
+	}
+
+	// The filters select the invoices, not their payments
+	events, err := w.Poll(ctx)
+	assert.NoError(err)
+	if assert.Len(events, 1) {
+		assert.Equal(100.0, events[0].Object.Amount)
+	}
+}
+
+func TestWatcherClockSkew(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	srv, hv := newFake(t)
+
+	// The Harvest clock runs a few minutes behind the local one
+	behind := time.Now().UTC().Add(-5 * time.Minute).Truncate(time.Second)
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "Old", CreatedAt: behind, UpdatedAt: behind})
+
+	w := hv.WatchInvoices()
+
+	// The first poll only finds the latest change
+	events, err := w.Poll(ctx)
+	assert.NoError(err)
+	assert.Empty(events)
+	assert.Equal(behind, w.Mark().UTC())
+
+	later := behind.Add(time.Minute)
+	srv.AddInvoice(&harvest.Invoice{Customer: &harvest.Customer{ID: 1}, Subject: "New", CreatedAt: later, UpdatedAt: later})
+
+	events, err = w.Poll(ctx)
+	assert.NoError(err)
+	if assert.Len(events, 1) {
+		assert.Equal(harvest.EventCreated, events[0].Type)
+		assert.Equal("New", events[0].Object.Subject)
+	}
+}